@@ -0,0 +1,28 @@
+package tlphone
+
+// IndexedResult is the Result for the input at position Index of a batch
+// or stream of words.
+type IndexedResult struct {
+	Index int
+	Input string
+	Result
+}
+
+// EncodeChan encodes every word received from in and sends the results, in
+// the order received, on the returned channel, which is closed once in is
+// closed and drained.
+func (k *TLPhone) EncodeChan(in <-chan string) <-chan IndexedResult {
+	out := make(chan IndexedResult)
+	go func() {
+		defer close(out)
+		var (
+			buf EncodeBuffer
+			i   int
+		)
+		for w := range in {
+			out <- IndexedResult{Index: i, Input: w, Result: k.EncodeInto(w, &buf)}
+			i++
+		}
+	}()
+	return out
+}
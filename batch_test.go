@@ -0,0 +1,37 @@
+package tlphone_test
+
+import (
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestEncodeChan(t *testing.T) {
+	var (
+		p  = tlphone.New()
+		in = make(chan string)
+	)
+	go func() {
+		for _, test := range encodeTests {
+			in <- test.input
+		}
+		close(in)
+	}()
+
+	var got []tlphone.IndexedResult
+	for r := range p.EncodeChan(in) {
+		got = append(got, r)
+	}
+	if len(got) != len(encodeTests) {
+		t.Fatalf("Result count mismatch: got=%d want=%d", len(got), len(encodeTests))
+	}
+	for i, test := range encodeTests {
+		r := got[i]
+		if r.Index != i || r.Input != test.input {
+			t.Errorf("Result %d mismatch: got index=%d input='%s' want index=%d input='%s'", i, r.Index, r.Input, i, test.input)
+		}
+		if r.Key0 != test.expectKey0 || r.Key1 != test.expectKey1 || r.Key2 != test.expectKey2 {
+			t.Errorf("Keys mismatch for input '%s': got=%v", test.input, r.Result)
+		}
+	}
+}
@@ -0,0 +1,383 @@
+package tlphone
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// text is input on its way through cleanStages. Unless from is nil, it
+// holds for each rune of s the indices of the runes of the original input
+// that rune came from, which the stages keep up to date for
+// EncodeReportingDrops.
+type text struct {
+	s    string
+	from [][]int
+}
+
+// A stage is one step of cleaning input.
+type stage struct {
+	apply func(k *TLPhone, t text) text
+	// prepares marks the stages of prepare, which come first. head and
+	// tail mark those preparePart runs only on a part of the input that
+	// starts or ends it.
+	prepares, head, tail bool
+}
+
+// cleanStages are the steps of clean, in the order it takes them.
+var cleanStages = []stage{
+	{apply: unescapeEntities, prepares: true},
+	{apply: trimHead, prepares: true, head: true},
+	{apply: trimTail, prepares: true, tail: true},
+	{apply: stripFormatting, prepares: true},
+	{apply: separateSymbols, prepares: true},
+	{apply: stripPrefix, prepares: true, head: true},
+	{apply: foldZero},
+	{apply: filterScript},
+	{apply: reorderMarks},
+	{apply: composeVowelSigns},
+	{apply: foldLegacy},
+	{apply: foldHomoglyphs},
+	{apply: dedupeModifiers},
+}
+
+// prepareStages are the stages of prepare, the first of cleanStages, and
+// scriptStages the ones after them.
+var prepareStages, scriptStages = splitPrepare(cleanStages)
+
+func splitPrepare(stages []stage) ([]stage, []stage) {
+	n := 0
+	for n < len(stages) && stages[n].prepares {
+		n++
+	}
+	return stages[:n], stages[n:]
+}
+
+// run applies stages to t in order. head and tail are as for preparePart.
+func (k *TLPhone) run(t text, stages []stage, head, tail bool) text {
+	for _, s := range stages {
+		if s.head && !head || s.tail && !tail {
+			continue
+		}
+		t = s.apply(k, t)
+	}
+	return t
+}
+
+// clean strips everything but Kannada script glyphs from input.
+func (k *TLPhone) clean(input string) string {
+	return k.cleanPart(input, true, true)
+}
+
+// cleanPart is clean for a part of a longer input, with head and tail as
+// for preparePart.
+func (k *TLPhone) cleanPart(input string, head, tail bool) string {
+	return k.run(text{s: input}, cleanStages, head, tail).s
+}
+
+// prepare trims input and removes the formatting characters, symbols and
+// prefixes that are not part of the word proper.
+func (k *TLPhone) prepare(input string) string {
+	return k.preparePart(input, true, true)
+}
+
+// preparePart is prepare for a part of a longer input. head and tail
+// report whether the part starts or ends the input, the only places
+// spaces and prefixes are stripped from.
+func (k *TLPhone) preparePart(input string, head, tail bool) string {
+	return k.run(text{s: input}, prepareStages, head, tail).s
+}
+
+// unescapeEntities decodes HTML entities when WithHTMLUnescape is on.
+func unescapeEntities(k *TLPhone, t text) text {
+	if !k.unescapeHTML {
+		return t
+	}
+	return t.unescapeHTML()
+}
+
+func trimHead(_ *TLPhone, t text) text {
+	return t.cut(len(t.s)-len(strings.TrimLeftFunc(t.s, unicode.IsSpace)), len(t.s))
+}
+
+func trimTail(_ *TLPhone, t text) text {
+	return t.cut(0, len(strings.TrimRightFunc(t.s, unicode.IsSpace)))
+}
+
+// stripFormatting removes the characters dropFormatting drops.
+func stripFormatting(_ *TLPhone, t text) text {
+	return t.mapRunes(dropFormatting)
+}
+
+// separateSymbols replaces emoji and other symbols with spaces when
+// WithStripEmoji is on, so they still separate the words around them.
+func separateSymbols(k *TLPhone, t text) text {
+	if !k.stripEmoji {
+		return t
+	}
+	return t.mapRunes(symbolToSpace)
+}
+
+// foldZero replaces the digit ೦ with the anusvara ಂ it is typed for, when
+// WithHomoglyphFolding is on, but only right after a consonant or vowel
+// sign, where an anusvara can stand. Elsewhere, as in ೧೦೦ or after a space,
+// it is a digit. It runs before filterScript drops the spaces.
+func foldZero(k *TLPhone, t text) text {
+	if !k.homoglyphs || !strings.ContainsRune(t.s, '\u0ce6') {
+		return t
+	}
+
+	var prev rune
+	return t.mapRunes(func(r rune) rune {
+		p := prev
+		prev = r
+		if r == '\u0ce6' && (isConsonant(p) || isVowelSign(p)) {
+			return '\u0c82'
+		}
+		return r
+	})
+}
+
+// filterScript removes every rune the encoder has no use for.
+func filterScript(k *TLPhone, t text) text {
+	return t.mapRunes(k.dropUnkept)
+}
+
+// reorderMarks sorts each run of combining marks in t into the order of
+// markRank, keeping marks of equal rank in input order, so marks typed in
+// another order still form the glyphs of the maps.
+func reorderMarks(_ *TLPhone, t text) text {
+	prev, ordered := -1, true
+	for _, r := range t.s {
+		n := markRank(r)
+		if n >= 0 && n < prev {
+			ordered = false
+			break
+		}
+		prev = n
+	}
+	if ordered {
+		return t
+	}
+
+	var (
+		rs   = []rune(t.s)
+		from [][]int
+	)
+	if t.from != nil {
+		from = append([][]int(nil), t.from...)
+	}
+	for i := 0; i < len(rs); {
+		j := i
+		for j < len(rs) && markRank(rs[j]) >= 0 {
+			j++
+		}
+		if j == i {
+			i++
+			continue
+		}
+		run := markRun{rs: rs[i:j]}
+		if from != nil {
+			run.from = from[i:j]
+		}
+		sort.Stable(run)
+		i = j
+	}
+	return text{s: string(rs), from: from}
+}
+
+// markRun sorts a run of combining marks by markRank, moving the origins
+// of the marks along with them.
+type markRun struct {
+	rs   []rune
+	from [][]int
+}
+
+func (m markRun) Len() int           { return len(m.rs) }
+func (m markRun) Less(a, b int) bool { return markRank(m.rs[a]) < markRank(m.rs[b]) }
+
+func (m markRun) Swap(a, b int) {
+	m.rs[a], m.rs[b] = m.rs[b], m.rs[a]
+	if m.from != nil {
+		m.from[a], m.from[b] = m.from[b], m.from[a]
+	}
+}
+
+// composeVowelSigns applies vowelSignComposer, skipping the common case of
+// input that has nothing to compose.
+func composeVowelSigns(_ *TLPhone, t text) text {
+	if !strings.ContainsAny(t.s, "\u0cd5\u0cd6") && !strings.Contains(t.s, "\u0cc6\u0cc2") {
+		return t
+	}
+	return vowelSignComposer.replace(t)
+}
+
+// foldLegacy applies legacyOrthography when WithLegacyOrthography is on.
+func foldLegacy(k *TLPhone, t text) text {
+	if !k.legacy {
+		return t
+	}
+	return legacyOrthography.replace(t)
+}
+
+// foldHomoglyphs applies homoglyphs when WithHomoglyphFolding is on.
+func foldHomoglyphs(k *TLPhone, t text) text {
+	if !k.homoglyphs {
+		return t
+	}
+	return homoglyphs.replace(t)
+}
+
+// dedupeModifiers collapses runs of the same modifier, such as a doubled
+// anusvara, into one when WithDedupeModifiers is on.
+func dedupeModifiers(k *TLPhone, t text) text {
+	if !k.dedupeMods {
+		return t
+	}
+
+	var prev rune
+	return t.mapRunes(func(r rune) rune {
+		if _, ok := k.modifiers[string(r)]; ok && r == prev {
+			return -1
+		}
+		prev = r
+		return r
+	})
+}
+
+// mapRunes is strings.Map for t: each rune is replaced by the rune mapping
+// returns, which comes from the same runes, or dropped if it is negative.
+func (t text) mapRunes(mapping func(r rune) rune) text {
+	if t.from == nil {
+		t.s = strings.Map(mapping, t.s)
+		return t
+	}
+
+	var (
+		b   strings.Builder
+		out = text{from: make([][]int, 0, len(t.from))}
+		i   int
+	)
+	for _, r := range t.s {
+		if r = mapping(r); r >= 0 {
+			b.WriteRune(r)
+			out.from = append(out.from, t.from[i])
+		}
+		i++
+	}
+	out.s = b.String()
+	return out
+}
+
+// cut returns the part of t from byte i to byte j.
+func (t text) cut(i, j int) text {
+	if t.from != nil {
+		n := utf8.RuneCountInString(t.s[:i])
+		t.from = t.from[n : n+utf8.RuneCountInString(t.s[i:j])]
+	}
+	t.s = t.s[i:j]
+	return t
+}
+
+// unescapeHTML is html.UnescapeString for t. The runes an entity decodes
+// to come from all the runes of the entity.
+func (t text) unescapeHTML() text {
+	if t.from == nil || !strings.Contains(t.s, "&") {
+		t.s = html.UnescapeString(t.s)
+		return t
+	}
+
+	var (
+		b   strings.Builder
+		out = text{from: make([][]int, 0, len(t.from))}
+		n   int
+	)
+	for s := t.s; s != ""; {
+		// Each entity starts at an & and ends before the next one, so each
+		// such part decodes on its own.
+		end := strings.IndexByte(s[1:], '&') + 1
+		if end == 0 {
+			end = len(s)
+		}
+		var (
+			dec  = html.UnescapeString(s[:end])
+			part = []rune(s[:end])
+			rs   = []rune(dec)
+			same int
+		)
+		// What follows the entity is left as it is.
+		for same < len(rs) && same < len(part)-1 && rs[len(rs)-1-same] == part[len(part)-1-same] {
+			same++
+		}
+		var from []int
+		for _, f := range t.from[n : n+len(part)-same] {
+			from = append(from, f...)
+		}
+		for range rs[:len(rs)-same] {
+			out.from = append(out.from, from)
+		}
+		out.from = append(out.from, t.from[n+len(part)-same:n+len(part)]...)
+		b.WriteString(dec)
+		n += len(part)
+		s = s[end:]
+	}
+	out.s = b.String()
+	return out
+}
+
+// A replacer is a strings.Replacer that can also tell where the runes of
+// its output came from.
+type replacer struct {
+	*strings.Replacer
+	oldnew []string
+}
+
+func newReplacer(oldnew ...string) replacer {
+	return replacer{strings.NewReplacer(oldnew...), oldnew}
+}
+
+// replace is Replace for t. Like Replace, it rewrites at each position the
+// first old string that matches there; the runes of the new string come
+// from all the runes of the old one.
+func (r replacer) replace(t text) text {
+	if t.from == nil {
+		t.s = r.Replace(t.s)
+		return t
+	}
+
+	var (
+		b    strings.Builder
+		out  = text{from: make([][]int, 0, len(t.from))}
+		i, n int // the byte and rune offsets in t.s
+	)
+next:
+	for i < len(t.s) {
+		for j := 0; j < len(r.oldnew); j += 2 {
+			old, rep := r.oldnew[j], r.oldnew[j+1]
+			if !strings.HasPrefix(t.s[i:], old) {
+				continue
+			}
+			var (
+				m    = utf8.RuneCountInString(old)
+				from []int
+			)
+			for _, f := range t.from[n : n+m] {
+				from = append(from, f...)
+			}
+			b.WriteString(rep)
+			for range rep {
+				out.from = append(out.from, from)
+			}
+			i, n = i+len(old), n+m
+			continue next
+		}
+		_, size := utf8.DecodeRuneInString(t.s[i:])
+		b.WriteString(t.s[i : i+size])
+		out.from = append(out.from, t.from[n])
+		i, n = i+size, n+1
+	}
+	out.s = b.String()
+	return out
+}
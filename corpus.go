@@ -0,0 +1,345 @@
+package tlphone
+
+import (
+	"sort"
+	"sync"
+)
+
+// Key returns the key at the given level: 0, 1 or 2. Any other level
+// returns Key2.
+func (r Result) Key(level int) string {
+	switch level {
+	case 0:
+		return r.Key0
+	case 1:
+		return r.Key1
+	}
+	return r.Key2
+}
+
+// ByteSize returns the combined length in bytes of the three keys.
+func (r Result) ByteSize() int {
+	return len(r.Key0) + len(r.Key1) + len(r.Key2)
+}
+
+// CorpusByteSize returns the sum of the ByteSize of the keys of all words,
+// the storage precomputed keys for the corpus take up.
+func (k *TLPhone) CorpusByteSize(words []string) int {
+	var (
+		buf EncodeBuffer
+		n   int
+	)
+	for _, w := range words {
+		n += k.EncodeInto(w, &buf).ByteSize()
+	}
+	return n
+}
+
+// Bucketize groups words by their key at the given level. Words within a
+// bucket keep their input order.
+func (k *TLPhone) Bucketize(words []string, level int) map[string][]string {
+	buckets := make(map[string][]string)
+	k.IndexInto(words, buckets, level)
+	return buckets
+}
+
+// IndexInto adds words to the caller's dst, keyed by their key at the given
+// level. Words are appended to any existing bucket, so several corpora can
+// be merged into one map.
+func (k *TLPhone) IndexInto(words []string, dst map[string][]string, level int) {
+	var buf EncodeBuffer
+	for _, w := range words {
+		key := k.EncodeInto(w, &buf).Key(level)
+		dst[key] = append(dst[key], w)
+	}
+}
+
+// BucketizeParallel is like Bucketize but encodes the words across a pool
+// of workers. Each worker handles a contiguous slice of words and the
+// partial buckets are merged in order, so the output equals Bucketize.
+func (k *TLPhone) BucketizeParallel(words []string, level, workers int) map[string][]string {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(words) {
+		workers = len(words)
+	}
+	if workers <= 1 {
+		return k.Bucketize(words, level)
+	}
+
+	var (
+		parts = make([]map[string][]string, workers)
+		size  = (len(words) + workers - 1) / workers
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < workers; i++ {
+		start := i * size
+		end := start + size
+		if end > len(words) {
+			end = len(words)
+		}
+		if start >= end {
+			break
+		}
+		wg.Add(1)
+		go func(i int, words []string) {
+			defer wg.Done()
+			parts[i] = k.Bucketize(words, level)
+		}(i, words[start:end])
+	}
+	wg.Wait()
+
+	buckets := make(map[string][]string)
+	for _, part := range parts {
+		for key, ws := range part {
+			buckets[key] = append(buckets[key], ws...)
+		}
+	}
+	return buckets
+}
+
+// CollisionRisk returns how many words in corpus share the key0 of input
+// but differ from it at key2, i.e. the likely false positives a key0
+// search for input would return.
+func (k *TLPhone) CollisionRisk(input string, corpus []string) int {
+	var (
+		r = k.EncodeResult(input)
+		n int
+	)
+	for _, w := range corpus {
+		c := k.EncodeResult(w)
+		if c.Key0 == r.Key0 && c.Key2 != r.Key2 {
+			n++
+		}
+	}
+	return n
+}
+
+// MostCommonKey returns the most frequent non-empty key at the given level
+// among words together with its count. Ties go to the smaller key.
+func (k *TLPhone) MostCommonKey(words []string, level int) (string, int) {
+	var (
+		counts = make(map[string]int)
+		best   string
+		n      int
+	)
+	for _, w := range words {
+		key := k.EncodeResult(w).Key(level)
+		if key == "" {
+			continue
+		}
+		counts[key]++
+		if c := counts[key]; c > n || c == n && key < best {
+			best, n = key, c
+		}
+	}
+	return best, n
+}
+
+// ClassHistogram tallies the code tokens of all words by Class, as
+// reported by EncodeClasses.
+func (k *TLPhone) ClassHistogram(words []string) map[string]int {
+	h := make(map[string]int)
+	for _, w := range words {
+		for _, c := range k.EncodeClasses(w) {
+			h[string(c)]++
+		}
+	}
+	return h
+}
+
+// FuzzyJoin returns the index pairs (i, j) for which left[i] and right[j]
+// share a non-empty key1, ordered by i and then j. Each distinct word is
+// encoded once.
+func (k *TLPhone) FuzzyJoin(left, right []string) [][2]int {
+	var (
+		keys = make(map[string]string)
+		buf  EncodeBuffer
+	)
+	key1 := func(w string) string {
+		key, ok := keys[w]
+		if !ok {
+			key = k.EncodeInto(w, &buf).Key1
+			keys[w] = key
+		}
+		return key
+	}
+
+	byKey := make(map[string][]int)
+	for j, w := range right {
+		if key := key1(w); key != "" {
+			byKey[key] = append(byKey[key], j)
+		}
+	}
+
+	var pairs [][2]int
+	for i, w := range left {
+		for _, j := range byKey[key1(w)] {
+			pairs = append(pairs, [2]int{i, j})
+		}
+	}
+	return pairs
+}
+
+// SimilarityGraph links every pair of distinct words whose Similarity
+// exceeds threshold and returns, for each word with at least one such
+// neighbour, its neighbours in corpus order. Each pair of words is
+// compared, so this takes time quadratic in len(words) and is meant for
+// clustering small to medium corpora, or the contents of one bucket.
+func (k *TLPhone) SimilarityGraph(words []string, threshold float64) map[string][]string {
+	var (
+		seen  = make(map[string]bool)
+		uniq  []string
+		toks  [][]string
+		graph = make(map[string][]string)
+	)
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		uniq = append(uniq, w)
+		toks = append(toks, k.tokens(k.clean(w)))
+	}
+
+	for i := range uniq {
+		for j := i + 1; j < len(uniq); j++ {
+			if similarity(toks[i], toks[j]) > threshold {
+				graph[uniq[i]] = append(graph[uniq[i]], uniq[j])
+				graph[uniq[j]] = append(graph[uniq[j]], uniq[i])
+			}
+		}
+	}
+	return graph
+}
+
+// SimilarityMatrix returns the n×n matrix of the Similarity of every pair
+// of words, with 1 on the diagonal. It is symmetric: each pair is scored
+// once, so this takes time and memory quadratic in len(words) and suits
+// small corpora, such as the candidates for one query.
+func (k *TLPhone) SimilarityMatrix(words []string) [][]float64 {
+	toks := make([][]string, len(words))
+	for i, w := range words {
+		toks[i] = k.tokens(k.clean(w))
+	}
+
+	m := make([][]float64, len(words))
+	for i := range m {
+		m[i] = make([]float64, len(words))
+		m[i][i] = 1
+		for j := 0; j < i; j++ {
+			m[i][j] = similarity(toks[i], toks[j])
+			m[j][i] = m[i][j]
+		}
+	}
+	return m
+}
+
+// Suggest returns the word of dict that sounds most like input by
+// Similarity, the earliest one on ties, or "" if no word shares any code
+// token with it.
+func (k *TLPhone) Suggest(input string, dict []string) string {
+	var (
+		toks      = k.tokens(k.clean(input))
+		best      string
+		bestScore float64
+	)
+	for _, w := range dict {
+		if s := similarity(toks, k.tokens(k.clean(w))); s > bestScore {
+			best, bestScore = w, s
+		}
+	}
+	return best
+}
+
+// EncodeCorrected replaces input with its Suggest correction from dict
+// and returns the keys of the corrected word together with its spelling.
+// Input for which dict has no suggestion is encoded and returned as is.
+func (k *TLPhone) EncodeCorrected(input string, dict []string) (Result, string) {
+	if w := k.Suggest(input, dict); w != "" {
+		input = w
+	}
+	return k.EncodeResult(input), input
+}
+
+// DedupeByKey2 collapses words that share a key2 and returns the first
+// word seen for each distinct non-empty key2, mapped to the number of
+// words that produced that key, repeats included.
+func (k *TLPhone) DedupeByKey2(words []string) map[string]int {
+	var (
+		first  = make(map[string]string)
+		counts = make(map[string]int)
+		buf    EncodeBuffer
+	)
+	for _, w := range words {
+		key := k.EncodeInto(w, &buf).Key2
+		if key == "" {
+			continue
+		}
+		rep, ok := first[key]
+		if !ok {
+			rep = w
+			first[key] = w
+		}
+		counts[rep]++
+	}
+	return counts
+}
+
+// MinDistinguishingPrefixes returns, for each word with a non-empty key at
+// the given level, the shortest prefix of that key no other key of words
+// starts with, which is enough to tell the words apart in a trie. Words
+// whose key equals, or is a prefix of, another key get the whole key.
+func (k *TLPhone) MinDistinguishingPrefixes(words []string, level int) map[string]string {
+	var (
+		keys = make(map[string]string)
+		uniq []string
+		buf  EncodeBuffer
+	)
+	for _, w := range words {
+		key := k.EncodeInto(w, &buf).Key(level)
+		if key == "" {
+			continue
+		}
+		if _, ok := keys[w]; !ok {
+			keys[w] = key
+			uniq = append(uniq, key)
+		}
+	}
+	sort.Strings(uniq)
+
+	// In sorted order the keys sharing the longest prefix with a key are
+	// its neighbours.
+	need := make(map[string]int, len(uniq))
+	for i := 1; i < len(uniq); i++ {
+		n := commonPrefixLen(uniq[i-1], uniq[i]) + 1
+		if n > need[uniq[i-1]] {
+			need[uniq[i-1]] = n
+		}
+		if n > need[uniq[i]] {
+			need[uniq[i]] = n
+		}
+	}
+
+	out := make(map[string]string, len(keys))
+	for w, key := range keys {
+		n := need[key]
+		if n < 1 {
+			n = 1
+		}
+		if n > len(key) {
+			n = len(key)
+		}
+		out[w] = key[:n]
+	}
+	return out
+}
+
+func commonPrefixLen(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,286 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+// mediumCorpus builds a few thousand synthetic words from consonant and
+// vowel sign combinations, with plenty of colliding keys.
+func mediumCorpus() []string {
+	var (
+		bases = []string{"ಕ", "ಗ", "ತ", "ದ", "ಮ", "ನ", "ಳ", "ಲ", "ಕ್ಕ", "ಟ್ಟ"}
+		signs = []string{"", "ಾ", "ಿ", "ು", "ೆ", "ೊ"}
+		words []string
+	)
+	for _, a := range bases {
+		for _, as := range signs {
+			for _, b := range bases {
+				for _, bs := range signs {
+					words = append(words, a+as+b+bs)
+				}
+			}
+		}
+	}
+	return words
+}
+
+func TestBucketize(t *testing.T) {
+	p := tlphone.New()
+	b := p.Bucketize([]string{"ತುಂಬಾ", "ತುಂಬ", "ಮಕ್ಕಳು"}, 0)
+	if got := b["03B"]; !reflect.DeepEqual(got, []string{"ತುಂಬಾ", "ತುಂಬ"}) {
+		t.Errorf("Bucket mismatch for key '03B': got=%v", got)
+	}
+	if got := b["MKL"]; !reflect.DeepEqual(got, []string{"ಮಕ್ಕಳು"}) {
+		t.Errorf("Bucket mismatch for key 'MKL': got=%v", got)
+	}
+}
+
+func TestBucketizeParallel(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		words = mediumCorpus()
+	)
+	for level := 0; level <= 2; level++ {
+		want := p.Bucketize(words, level)
+		for _, workers := range []int{0, 1, 3, 8, len(words) + 1} {
+			got := p.BucketizeParallel(words, level, workers)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BucketizeParallel mismatch at level %d with %d workers", level, workers)
+			}
+		}
+	}
+}
+
+func TestCollisionRisk(t *testing.T) {
+	var (
+		p      = tlphone.New()
+		corpus = []string{"ತುಂಬಾ", "ತಂಬಾ", "ತುಂಬ", "ತಿಂಬಾ", "ಮಕ್ಕಳು", "ಮಕಳು"}
+	)
+	tests := []struct {
+		input  string
+		expect int
+	}{
+		{"ತುಂಬಾ", 2},
+		{"ಮಕ್ಕಳು", 1},
+		{"ಅನುಗ್ರಹ", 0},
+	}
+	for _, test := range tests {
+		if got := p.CollisionRisk(test.input, corpus); got != test.expect {
+			t.Errorf("CollisionRisk mismatch for input '%s': got=%d want=%d", test.input, got, test.expect)
+		}
+	}
+}
+
+func TestMostCommonKey(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		words = []string{"ತುಂಬಾ", "ಮಕ್ಕಳು", "ತಂಬಾ", "ತುಂಬ", "ಮಕಳು", "abc", "", "ತಿಂಬಾ"}
+	)
+	tests := []struct {
+		level  int
+		expect string
+		count  int
+	}{
+		{0, "03B", 4},
+		{1, "03B", 4},
+		{2, "053B", 2},
+	}
+	for _, test := range tests {
+		key, n := p.MostCommonKey(words, test.level)
+		if key != test.expect || n != test.count {
+			t.Errorf("MostCommonKey mismatch at level %d: got=%s,%d want=%s,%d", test.level, key, n, test.expect, test.count)
+		}
+	}
+	if key, n := p.MostCommonKey(nil, 0); key != "" || n != 0 {
+		t.Errorf("MostCommonKey mismatch for empty corpus: got=%s,%d", key, n)
+	}
+}
+
+func TestIndexInto(t *testing.T) {
+	var (
+		p   = tlphone.New()
+		dst = make(map[string][]string)
+	)
+	p.IndexInto([]string{"ತುಂಬಾ", "ಮಕ್ಕಳು"}, dst, 0)
+	p.IndexInto([]string{"ತಂಬಾ", "ಮಕಳು", "ವೃತ್ತಿ"}, dst, 0)
+
+	want := map[string][]string{
+		"03B": {"ತುಂಬಾ", "ತಂಬಾ"},
+		"MKL": {"ಮಕ್ಕಳು", "ಮಕಳು"},
+		"VR0": {"ವೃತ್ತಿ"},
+	}
+	if !reflect.DeepEqual(dst, want) {
+		t.Errorf("IndexInto mismatch: got=%v want=%v", dst, want)
+	}
+}
+
+func TestClassHistogram(t *testing.T) {
+	p := tlphone.New()
+	got := p.ClassHistogram([]string{"ಮಕ್ಕಳು", "ತುಂಬಾ", "ಅನುಗ್ರಹ"})
+	want := map[string]int{
+		"consonant": 8,
+		"compound":  1,
+		"modifier":  3,
+		"nasal":     1,
+		"vowel":     1,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ClassHistogram mismatch: got=%v want=%v", got, want)
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		words []string
+		total int
+	)
+	for _, test := range encodeTests {
+		want := len(test.expectKey0) + len(test.expectKey1) + len(test.expectKey2)
+		if got := p.EncodeResult(test.input).ByteSize(); got != want {
+			t.Errorf("ByteSize mismatch for input '%s': got=%d want=%d", test.input, got, want)
+		}
+		words = append(words, test.input)
+		total += want
+	}
+	if got := p.CorpusByteSize(words); got != total {
+		t.Errorf("CorpusByteSize mismatch: got=%d want=%d", got, total)
+	}
+	if got := p.CorpusByteSize([]string{"ಮಕ್ಕಳು", "ತುಂಬಾ"}); got != 23 {
+		t.Errorf("CorpusByteSize mismatch: got=%d want=23", got)
+	}
+}
+
+func TestFuzzyJoin(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		left  = []string{"ಬಂಗಾರಾ", "ಮಕ್ಕಳು", "abc", "ವೃತ್ತಿ"}
+		right = []string{"ಅನುಗ್ರಹ", "xyz", "ಮಕಳು", "ತುಂಬಾ"}
+	)
+	want := [][2]int{{1, 2}}
+	if got := p.FuzzyJoin(left, right); !reflect.DeepEqual(got, want) {
+		t.Errorf("FuzzyJoin mismatch: got=%v want=%v", got, want)
+	}
+
+	want = [][2]int{{0, 1}, {0, 2}, {1, 1}, {1, 2}}
+	if got := p.FuzzyJoin([]string{"ತುಂಬಾ", "ತಂಬ"}, []string{"ಮಕ್ಕಳು", "ತುಂಬ", "ತಿಂಬಾ"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("FuzzyJoin mismatch: got=%v want=%v", got, want)
+	}
+}
+
+func TestSimilarityGraph(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		words = []string{"ಮಕ್ಕಳು", "ನೀರು", "ಮಕ್ಕಳ", "ಕಣ್ಣು", "ಮಕ್ಕಳು"}
+		want  = map[string][]string{
+			"ಮಕ್ಕಳು": {"ಮಕ್ಕಳ"},
+			"ಮಕ್ಕಳ":  {"ಮಕ್ಕಳು"},
+		}
+	)
+	if got := p.SimilarityGraph(words, 0.7); !reflect.DeepEqual(got, want) {
+		t.Errorf("SimilarityGraph mismatch: got=%v want=%v", got, want)
+	}
+	if got := p.SimilarityGraph(words, 0.75); len(got) != 0 {
+		t.Errorf("SimilarityGraph mismatch at threshold 0.75: got=%v want=map[]", got)
+	}
+}
+
+func TestEncodeCorrected(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"ಮಕ್ಕಲು", "ಮಕ್ಕಳು"},
+		{"ನೀರ", "ನೀರು"},
+		{"ಕಣ್ಣು", "ಕಣ್ಣು"},
+		{"ಹ", "ಹ"},
+	}
+
+	var (
+		p    = tlphone.New()
+		dict = []string{"ನೀರು", "ಮಕ್ಕಳು", "ಕಣ್ಣು", "ಮಳೆ"}
+	)
+	for _, test := range tests {
+		r, got := p.EncodeCorrected(test.input, dict)
+		if got != test.expect {
+			t.Errorf("Correction mismatch for input '%s': got=%s want=%s", test.input, got, test.expect)
+		}
+		if want := p.EncodeResult(test.expect); r != want {
+			t.Errorf("Corrected keys mismatch for input '%s': got=%v want=%v", test.input, r, want)
+		}
+	}
+}
+
+func TestDedupeByKey2(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		words = []string{"ಅನ್ನ", "ಮಕ್ಕಳು", "ಅನ್ನ", "\u200fಅನ್ನ", "ಮಕ್\u200dಕಳು", "ನೀರು", "abc"}
+		want  = map[string]int{"ಅನ್ನ": 3, "ಮಕ್ಕಳು": 2, "ನೀರು": 1}
+	)
+	if got := p.DedupeByKey2(words); !reflect.DeepEqual(got, want) {
+		t.Errorf("DedupeByKey2 mismatch: got=%v want=%v", got, want)
+	}
+}
+
+func TestMinDistinguishingPrefixes(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		words = []string{"ಮಕ್ಕಳು", "ಮಕ್ಕಳ", "ಮಗಳು", "ನೀರು", "ಅನ್ನ", "ಅನ್ನ", "\u200fಅನ್ನ", "abc"}
+	)
+	tests := []struct {
+		level  int
+		expect map[string]string
+	}{
+		{2, map[string]string{
+			"ಮಕ್ಕಳು":     "MK2L15",
+			"ಮಕ್ಕಳ":      "MK2L1",
+			"ಮಗಳು":       "MKL",
+			"ನೀರು":       "N",
+			"ಅನ್ನ":       "AN2",
+			"\u200fಅನ್ನ": "AN2",
+		}},
+		{0, map[string]string{
+			"ಮಕ್ಕಳು":     "MKL",
+			"ಮಕ್ಕಳ":      "MKL",
+			"ಮಗಳು":       "MKL",
+			"ನೀರು":       "N",
+			"ಅನ್ನ":       "AN",
+			"\u200fಅನ್ನ": "AN",
+		}},
+	}
+	for _, test := range tests {
+		if got := p.MinDistinguishingPrefixes(words, test.level); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("Prefix mismatch at level %d: got=%v want=%v", test.level, got, test.expect)
+		}
+	}
+}
+
+func TestSimilarityMatrix(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		words = []string{"ಮಕ್ಕಳು", "ಮಕ್ಕಳ", "ನೀರು", ""}
+		want  = [][]float64{
+			{1, 0.75, 0.25, 0},
+			{0.75, 1, 0, 0},
+			{0.25, 0, 1, 0},
+			{0, 0, 0, 1},
+		}
+	)
+	got := p.SimilarityMatrix(words)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SimilarityMatrix mismatch: got=%v want=%v", got, want)
+	}
+	for i := range got {
+		if got[i][i] != 1 {
+			t.Errorf("SimilarityMatrix diagonal mismatch at %d: got=%v want=1", i, got[i][i])
+		}
+		for j := range got {
+			if got[i][j] != got[j][i] {
+				t.Errorf("SimilarityMatrix not symmetric at %d,%d: got=%v and %v", i, j, got[i][j], got[j][i])
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package tlphone
+
+import "fmt"
+
+// profiles are the rule profiles of NewProfile, as options applied before
+// the caller's own.
+var profiles = map[string][]Option{
+	"standard": nil,
+	"coastal":  {withCodes(coastalCodes)},
+}
+
+// coastalCodes are the codes the "coastal" profile changes. Speech along
+// the coast around Mangaluru and Udupi does not keep the retroflex ಳ apart
+// from ಲ, nor the sibilants ಶ and ಷ from ಸ, so their spellings merge. The
+// compounds holding them have codes of their own and are listed as well.
+var coastalCodes = map[string]string{
+	"ಳ": "L", "ಳ್ಳ": "L2",
+	"ಶ": "S", "ಷ": "S", "ಶ್ಶ": "S", "ಕ್ಷ": "KS",
+}
+
+// NewProfile is like New but starts from a named rule profile for one
+// region's pronunciation, with opts applied after it. The profiles are:
+//
+//   - standard: the rules of New
+//   - coastal: ಳ encodes like ಲ and ಶ and ಷ like ಸ, as in the coastal
+//     dialects that do not tell them apart
+//
+// It returns an error for any other name.
+func NewProfile(name string, opts ...Option) (*TLPhone, error) {
+	base, ok := profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("tlphone: unknown profile %q", name)
+	}
+	return New(append(append([]Option(nil), base...), opts...)...), nil
+}
+
+// withCodes changes the code of each glyph of codes that is a consonant or
+// compound in the maps.
+func withCodes(codes map[string]string) Option {
+	return func(k *TLPhone) {
+		for g, c := range codes {
+			if _, ok := k.consonants[g]; ok {
+				k.consonants[g] = c
+			}
+			if _, ok := k.compounds[g]; ok {
+				k.compounds[g] = c
+			}
+		}
+	}
+}
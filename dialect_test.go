@@ -0,0 +1,46 @@
+package tlphone_test
+
+import (
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestNewProfile(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectStd     string
+		expectCoastal string
+	}{
+		{"ಬಳೆ", "BL16", "BL6"},
+		{"ಶಾಲೆ", "S1L6", "SL6"},
+		{"ಕಳ್ಳ", "KL12", "KL2"},
+		{"ಮಕ್ಕಳು", "MK2L15", "MK2L5"},
+		{"ಅನ್ನ", "AN2", "AN2"},
+		{"ಅಧ್ಯಕ್ಷ", "A0YKS1", "A0YKS"},
+	}
+
+	std, err := tlphone.NewProfile("standard")
+	if err != nil {
+		t.Fatalf("NewProfile failed for 'standard': %v", err)
+	}
+	coastal, err := tlphone.NewProfile("coastal")
+	if err != nil {
+		t.Fatalf("NewProfile failed for 'coastal': %v", err)
+	}
+	for _, test := range tests {
+		if got := std.EncodeResult(test.input).Key2; got != test.expectStd {
+			t.Errorf("Key2 mismatch for input '%s' in standard profile: got=%s want=%s", test.input, got, test.expectStd)
+		}
+		if got := coastal.EncodeResult(test.input).Key2; got != test.expectCoastal {
+			t.Errorf("Key2 mismatch for input '%s' in coastal profile: got=%s want=%s", test.input, got, test.expectCoastal)
+		}
+	}
+
+	if p, err := tlphone.NewProfile("inland"); err == nil || p != nil {
+		t.Errorf("NewProfile accepted unknown profile 'inland': got=%v, %v", p, err)
+	}
+	if p, _ := tlphone.NewProfile("coastal", tlphone.WithLoanwords(true)); p.EncodeResult("ಜ಼ಳ").Key2 != "GL" {
+		t.Errorf("Key2 mismatch for input 'ಜ಼ಳ' in coastal profile with loanwords: got=%s want=GL", p.EncodeResult("ಜ಼ಳ").Key2)
+	}
+}
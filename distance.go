@@ -0,0 +1,64 @@
+package tlphone
+
+import "strings"
+
+// FixedPad fills the keys of EncodeFixed that are shorter than the length
+// asked for. It is not a code, so padding never matches a real code.
+const FixedPad = '-'
+
+// EncodeFixed returns the key0 of input cut or padded with FixedPad to
+// exactly length bytes, for fixed-width storage like Soundex codes.
+func (k *TLPhone) EncodeFixed(input string, length int) string {
+	if length <= 0 {
+		return ""
+	}
+	key := k.EncodeResult(input).Key0
+	if len(key) >= length {
+		return key[:length]
+	}
+	return key + strings.Repeat(string(FixedPad), length-len(key))
+}
+
+// FixedKeyHamming returns the number of positions at which the fixed keys
+// of a and b, as returned by EncodeFixed, differ.
+func (k *TLPhone) FixedKeyHamming(a, b string, length int) int {
+	ka, kb := k.EncodeFixed(a, length), k.EncodeFixed(b, length)
+	n := 0
+	for i := 0; i < len(ka); i++ {
+		if ka[i] != kb[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// DeletionStability returns the fraction of the single character deletions
+// from input that leave its key0 unchanged, where a character is a letter
+// together with its signs, as in ಕಾ or ಕ್, the unit a typist drops. Words
+// whose key0 survives a dropped character score high. It returns 0 for
+// input without characters.
+func (k *TLPhone) DeletionStability(input string) float64 {
+	var (
+		clean  = k.clean(input)
+		chars  = clusters(clean)
+		buf    EncodeBuffer
+		key0   = k.encode(clean, &buf).Key0
+		stable int
+		b      strings.Builder
+	)
+	if len(chars) == 0 {
+		return 0
+	}
+	for i := range chars {
+		b.Reset()
+		for j, c := range chars {
+			if j != i {
+				b.WriteString(c)
+			}
+		}
+		if k.encode(b.String(), &buf).Key0 == key0 {
+			stable++
+		}
+	}
+	return float64(stable) / float64(len(chars))
+}
@@ -0,0 +1,73 @@
+package tlphone_test
+
+import (
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestEncodeFixed(t *testing.T) {
+	tests := []struct {
+		input  string
+		length int
+		expect string
+	}{
+		{"ಅನುಗ್ರಹ", 4, "ANKR"},
+		{"ಮಕ್ಕಳು", 4, "MKL-"},
+		{"ಮಕ್ಕಳು", 3, "MKL"},
+		{"", 2, "--"},
+		{"ಮಕ್ಕಳು", 0, ""},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.EncodeFixed(test.input, test.length); got != test.expect {
+			t.Errorf("EncodeFixed mismatch for input '%s' (length=%d): got=%s want=%s", test.input, test.length, got, test.expect)
+		}
+	}
+}
+
+func TestFixedKeyHamming(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		length int
+		expect int
+	}{
+		{"ಮಕ್ಕಳು", "ಮಕಳು", 4, 0},
+		// MKL- and BKR-
+		{"ಮಕ್ಕಳು", "ಬಕ್ಕರು", 4, 2},
+		// ANKRH- and ANKR--
+		{"ಅನುಗ್ರಹ", "ಅನುಗ್ರ", 6, 1},
+		{"ಅನುಗ್ರಹ", "ಮಕ್ಕಳು", 0, 0},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.FixedKeyHamming(test.a, test.b, test.length); got != test.expect {
+			t.Errorf("FixedKeyHamming mismatch for '%s' and '%s' (length=%d): got=%d want=%d", test.a, test.b, test.length, got, test.expect)
+		}
+	}
+}
+
+func TestDeletionStability(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect float64
+	}{
+		{"ಕಾಲು", 0},
+		{"ಕೀ", 0},
+		{"ಮಕ್ಕಳು", 0.5},
+		{"ಕಲ", 0},
+		{"", 0},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.DeletionStability(test.input); got != test.expect {
+			t.Errorf("DeletionStability mismatch for input '%s': got=%v want=%v", test.input, got, test.expect)
+		}
+	}
+	if stable, fragile := p.DeletionStability("ಮಕ್ಕಳು"), p.DeletionStability("ಕಲ"); stable <= fragile {
+		t.Errorf("DeletionStability of 'ಮಕ್ಕಳು' (%v) not above 'ಕಲ' (%v)", stable, fragile)
+	}
+}
@@ -0,0 +1,146 @@
+package tlphone
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// FTS5Tokens returns a space separated token string for input, meant to be
+// stored in a SQLite FTS5 column alongside the original text. It holds the
+// three keys, prefixed "k0", "k1" and "k2", and every code bigram of key2
+// prefixed "g", e.g. "k0MKL k1MKL1 k2MK2L15 gMK2 gK2L1 gL15". The prefixes
+// keep keys from matching n-grams or one another.
+//
+// Under the default options every token is a bareword of ASCII letters and
+// digits, so the "ascii" or "unicode61" tokenizer is enough; both fold
+// case, the prefixes stay distinct either way:
+//
+//	CREATE VIRTUAL TABLE names_fts USING fts5(
+//		phonetic, content='names', content_rowid='id', tokenize='ascii');
+//
+// Query with a token of the wanted level, for example MATCH 'k1MKL1' for a
+// key1 match, or with "g" tokens combined by AND for substring matching.
+func (k *TLPhone) FTS5Tokens(input string) string {
+	r := k.EncodeResult(input)
+	if r.Key2 == "" {
+		return ""
+	}
+
+	toks := []string{"k0" + r.Key0, "k1" + r.Key1, "k2" + r.Key2}
+	seen := make(map[string]bool)
+	for _, g := range k.CodeNGrams(input, 2) {
+		if !seen[g] {
+			seen[g] = true
+			toks = append(toks, "g"+g)
+		}
+	}
+	return strings.Join(toks, " ")
+}
+
+var errShortBinary = errors.New("tlphone: truncated binary results")
+
+// EncodeResultsBinary serializes results into a compact binary form that
+// DecodeResultsBinary reads back. The format is the number of results as a
+// uvarint, then for each result its Index as a varint followed by Input,
+// Key0, Key1 and Key2, each as a uvarint length and the string's bytes.
+func EncodeResultsBinary(results []IndexedResult) ([]byte, error) {
+	b := make([]byte, 0, 16*len(results))
+	b = appendUvarint(b, uint64(len(results)))
+	for _, r := range results {
+		b = appendVarint(b, int64(r.Index))
+		for _, s := range []string{r.Input, r.Key0, r.Key1, r.Key2} {
+			b = appendUvarint(b, uint64(len(s)))
+			b = append(b, s...)
+		}
+	}
+	return b, nil
+}
+
+// DecodeResultsBinary parses results serialized by EncodeResultsBinary.
+func DecodeResultsBinary(data []byte) ([]IndexedResult, error) {
+	n, m := binary.Uvarint(data)
+	if m <= 0 {
+		return nil, errShortBinary
+	}
+	data = data[m:]
+	// Every result takes at least five bytes, which bounds a corrupt count.
+	if n > uint64(len(data)/5) {
+		return nil, errShortBinary
+	}
+
+	results := make([]IndexedResult, 0, n)
+	for i := uint64(0); i < n; i++ {
+		idx, m := binary.Varint(data)
+		if m <= 0 {
+			return nil, errShortBinary
+		}
+		data = data[m:]
+
+		var fields [4]string
+		for j := range fields {
+			l, m := binary.Uvarint(data)
+			if m <= 0 || l > uint64(len(data)-m) {
+				return nil, errShortBinary
+			}
+			fields[j] = string(data[m : m+int(l)])
+			data = data[m+int(l):]
+		}
+		results = append(results, IndexedResult{
+			Index:  int(idx),
+			Input:  fields[0],
+			Result: Result{Key0: fields[1], Key1: fields[2], Key2: fields[3]},
+		})
+	}
+	if len(data) != 0 {
+		return nil, errors.New("tlphone: trailing data after binary results")
+	}
+	return results, nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(b, tmp[:binary.PutUvarint(tmp[:], v)]...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	return append(b, tmp[:binary.PutVarint(tmp[:], v)]...)
+}
+
+// synonymEscaper escapes the characters the Solr synonym format treats
+// specially.
+var synonymEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `=>`, `\=>`)
+
+// SynonymLines groups words by key1 and returns one line per group of two
+// or more distinct words in the Solr synonym format read by Lucene's
+// SynonymGraphFilter, e.g. "ಅನ್ನ, ಅನ", making the words of a group
+// equivalent. Groups come in the order of their first word in words, and
+// words within a line in the order they first appear.
+func (k *TLPhone) SynonymLines(words []string) []string {
+	var (
+		groups = make(map[string][]string)
+		order  []string
+		seen   = make(map[string]bool)
+		buf    EncodeBuffer
+	)
+	for _, w := range words {
+		key := k.EncodeInto(w, &buf).Key1
+		if key == "" || seen[w] {
+			continue
+		}
+		seen[w] = true
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], synonymEscaper.Replace(w))
+	}
+
+	var lines []string
+	for _, key := range order {
+		if g := groups[key]; len(g) > 1 {
+			lines = append(lines, strings.Join(g, ", "))
+		}
+	}
+	return lines
+}
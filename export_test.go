@@ -0,0 +1,85 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestFTS5Tokens(t *testing.T) {
+	p := tlphone.New()
+	for _, test := range encodeTests {
+		toks := strings.Fields(p.FTS5Tokens(test.input))
+		for _, want := range []string{"k0" + test.expectKey0, "k1" + test.expectKey1, "k2" + test.expectKey2} {
+			if !contains(toks, want) {
+				t.Errorf("FTS5 token missing for input '%s': want=%s in %v", test.input, want, toks)
+			}
+		}
+	}
+
+	if got, want := p.FTS5Tokens("ಮಕ್ಕಳು"), "k0MKL k1MKL1 k2MK2L15 gMK2 gK2L1 gL15"; got != want {
+		t.Errorf("FTS5 tokens mismatch for input 'ಮಕ್ಕಳು': got=%s want=%s", got, want)
+	}
+	if got := p.FTS5Tokens("abc"); got != "" {
+		t.Errorf("FTS5 tokens for non-Tulu input: got=%s want empty", got)
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestResultsBinary(t *testing.T) {
+	var (
+		p       = tlphone.New()
+		results []tlphone.IndexedResult
+	)
+	for i, test := range encodeTests {
+		results = append(results, tlphone.IndexedResult{Index: i, Input: test.input, Result: p.EncodeResult(test.input)})
+	}
+	results = append(results, tlphone.IndexedResult{Index: -1})
+
+	data, err := tlphone.EncodeResultsBinary(results)
+	if err != nil {
+		t.Fatalf("EncodeResultsBinary failed: %v", err)
+	}
+	got, err := tlphone.DecodeResultsBinary(data)
+	if err != nil {
+		t.Fatalf("DecodeResultsBinary failed: %v", err)
+	}
+	if !reflect.DeepEqual(got, results) {
+		t.Errorf("Round trip mismatch: got=%v want=%v", got, results)
+	}
+
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0), {0xff, 0xff, 0xff, 0xff, 0x0f}} {
+		if _, err := tlphone.DecodeResultsBinary(bad); err == nil {
+			t.Errorf("DecodeResultsBinary accepted corrupt data %v", bad)
+		}
+	}
+
+	data, err = tlphone.EncodeResultsBinary(nil)
+	if err != nil {
+		t.Fatalf("EncodeResultsBinary failed for no results: %v", err)
+	}
+	if got, err := tlphone.DecodeResultsBinary(data); err != nil || len(got) != 0 {
+		t.Errorf("Round trip mismatch for no results: got=%v err=%v", got, err)
+	}
+}
+
+func TestSynonymLines(t *testing.T) {
+	var (
+		p     = tlphone.New()
+		words = []string{"ಅನ್ನ", "ಮಕ್ಕಳು", "ಅನ", "ನೀರು", "ಮಕ್ಕಳ", "ಅನ್ನ", "ಅಣ್ಣ", "ಮಕ್ಕ,ಳು"}
+		want  = []string{"ಅನ್ನ, ಅನ", `ಮಕ್ಕಳು, ಮಕ್ಕಳ, ಮಕ್ಕ\,ಳು`}
+	)
+	if got := p.SynonymLines(words); !reflect.DeepEqual(got, want) {
+		t.Errorf("SynonymLines mismatch: got=%q want=%q", got, want)
+	}
+}
@@ -0,0 +1,50 @@
+package tlphone
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
+
+// ShortIDLen is the length of the IDs returned by ShortID.
+const ShortIDLen = 8
+
+// ShortID returns a short URL-safe token for input, made of the first
+// ShortIDLen characters of the unpadded base64url encoding of the 64-bit
+// FNV-1a hash of its key0. Words with equal key0 always share an ID. The
+// ID carries 48 bits of the hash, so for a corpus of n distinct key0s the
+// chance of any two colliding is about n²/2⁴⁹: negligible for thousands of
+// keys, but around one in two past sixteen million.
+func (k *TLPhone) ShortID(input string) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], hashKey(k.EncodeResult(input).Key0))
+	return base64.RawURLEncoding.EncodeToString(b[:])[:ShortIDLen]
+}
+
+// GroupColor returns a CSS hex color such as "#3fa2c7" for input, derived
+// from the same hash of its key0 as ShortID, so that a UI can give words
+// with equal key0 the same swatch. Unrelated words get colors that look
+// random; with 2²⁴ colors, near matches are only likely across thousands
+// of groups.
+func (k *TLPhone) GroupColor(input string) string {
+	h := hashKey(k.EncodeResult(input).Key0)
+	return fmt.Sprintf("#%06x", (h^h>>24^h>>48)&0xffffff)
+}
+
+// Shard returns the shard in [0, n) that input belongs to when a phonetic
+// index is split across n backends, derived from the same hash of its
+// key0 as ShortID, so words with equal key0 always land on the same
+// shard. It returns 0 if n is less than 1.
+func (k *TLPhone) Shard(input string, n int) int {
+	if n < 1 {
+		return 0
+	}
+	return int(hashKey(k.EncodeResult(input).Key0) % uint64(n))
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
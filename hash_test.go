@@ -0,0 +1,93 @@
+package tlphone_test
+
+import (
+	"regexp"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestShortID(t *testing.T) {
+	var (
+		p      = tlphone.New()
+		urlOK  = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+		seen   = map[string]string{}
+		inputs = []string{"ತುಂಬಾ", "ಮಕ್ಕಳು", "ಬಂಗಾರಾ", "ಅನುಗ್ರಹ", "ವೃತ್ತಿ", "ಅಧ್ಯಕ್ಷ", ""}
+	)
+	for _, input := range inputs {
+		id := p.ShortID(input)
+		if len(id) != tlphone.ShortIDLen {
+			t.Errorf("ShortID length mismatch for input '%s': got=%d want=%d", input, len(id), tlphone.ShortIDLen)
+		}
+		if !urlOK.MatchString(id) {
+			t.Errorf("ShortID not URL-safe for input '%s': got=%s", input, id)
+		}
+		if again := p.ShortID(input); again != id {
+			t.Errorf("ShortID not deterministic for input '%s': got=%s then %s", input, id, again)
+		}
+		if prev, ok := seen[id]; ok {
+			t.Errorf("ShortID collision between '%s' and '%s': %s", prev, input, id)
+		}
+		seen[id] = input
+	}
+
+	if a, b := p.ShortID("ತುಂಬಾ"), p.ShortID("ತಂಬ"); a != b {
+		t.Errorf("ShortID mismatch for key0-equal words: got=%s and %s", a, b)
+	}
+}
+
+func TestGroupColor(t *testing.T) {
+	var (
+		p      = tlphone.New()
+		hexOK  = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+		seen   = map[string]string{}
+		inputs = []string{"ತುಂಬಾ", "ಮಕ್ಕಳು", "ಬಂಗಾರಾ", "ಅನುಗ್ರಹ", "ವೃತ್ತಿ", "ಅಧ್ಯಕ್ಷ", ""}
+	)
+	for _, input := range inputs {
+		c := p.GroupColor(input)
+		if !hexOK.MatchString(c) {
+			t.Errorf("GroupColor not a hex color for input '%s': got=%s", input, c)
+		}
+		if prev, ok := seen[c]; ok {
+			t.Errorf("GroupColor collision between '%s' and '%s': %s", prev, input, c)
+		}
+		seen[c] = input
+	}
+
+	if a, b := p.GroupColor("ತುಂಬಾ"), p.GroupColor("ತಂಬ"); a != b {
+		t.Errorf("GroupColor mismatch for key0-equal words: got=%s and %s", a, b)
+	}
+}
+
+func TestShard(t *testing.T) {
+	p := tlphone.New()
+	if a, b := p.Shard("ತುಂಬಾ", 8), p.Shard("ತಂಬ", 8); a != b {
+		t.Errorf("Shard mismatch for key0-equal words: got=%d and %d", a, b)
+	}
+	if got := p.Shard("ತುಂಬಾ", 0); got != 0 {
+		t.Errorf("Shard mismatch for n=0: got=%d want=0", got)
+	}
+
+	const n = 4
+	var (
+		counts [n]int
+		keys   = map[string]bool{}
+	)
+	for _, w := range mediumCorpus() {
+		key := p.EncodeResult(w).Key0
+		if keys[key] {
+			continue
+		}
+		keys[key] = true
+		s := p.Shard(w, n)
+		if s < 0 || s >= n {
+			t.Fatalf("Shard out of range for input '%s': got=%d", w, s)
+		}
+		counts[s]++
+	}
+	for s, c := range counts {
+		if c < len(keys)/n/2 {
+			t.Errorf("Shard %d underfilled: got=%d of %d keys", s, c, len(keys))
+		}
+	}
+}
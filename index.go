@@ -0,0 +1,100 @@
+package tlphone
+
+import "sort"
+
+// Index is an in-memory phonetic index of words, bucketed by key2. It is
+// not safe for concurrent use.
+type Index struct {
+	k       *TLPhone
+	buckets map[string][]string
+	// key0s maps the key2 of each bucket to the key0 its words encode to,
+	// which Compact groups by.
+	key0s map[string]string
+	// aliases maps the key2 of buckets merged away by Compact to the key2
+	// of the bucket that absorbed them.
+	aliases map[string]string
+}
+
+// NewIndex returns an empty index that encodes words with k.
+func (k *TLPhone) NewIndex() *Index {
+	return &Index{
+		k:       k,
+		buckets: make(map[string][]string),
+		key0s:   make(map[string]string),
+		aliases: make(map[string]string),
+	}
+}
+
+// Add adds words to the index.
+func (idx *Index) Add(words ...string) {
+	for _, w := range words {
+		r := idx.k.EncodeResult(w)
+		key := idx.resolve(r.Key2)
+		if _, ok := idx.buckets[key]; !ok {
+			idx.key0s[key] = r.Key0
+		}
+		idx.buckets[key] = append(idx.buckets[key], w)
+	}
+}
+
+// Lookup returns the words in the bucket input belongs to.
+func (idx *Index) Lookup(input string) []string {
+	return idx.buckets[idx.resolve(idx.k.EncodeResult(input).Key2)]
+}
+
+// Len returns the number of buckets in the index.
+func (idx *Index) Len() int {
+	return len(idx.buckets)
+}
+
+func (idx *Index) resolve(key2 string) string {
+	if c, ok := idx.aliases[key2]; ok {
+		return c
+	}
+	return key2
+}
+
+// Compact merges all buckets whose words share a key0 into one and returns
+// the number of buckets merged away. The canonical bucket of a group is the
+// one holding the most words, the smallest key2 breaking ties; the words of
+// the others are appended to it in key2 order. Words added later, and
+// lookups, for a merged key2 go to the canonical bucket.
+func (idx *Index) Compact() int {
+	groups := make(map[string][]string)
+	for key2 := range idx.buckets {
+		key0 := idx.key0s[key2]
+		groups[key0] = append(groups[key0], key2)
+	}
+
+	merges := 0
+	for _, keys := range groups {
+		if len(keys) < 2 {
+			continue
+		}
+		sort.Strings(keys)
+		canon := keys[0]
+		for _, key := range keys[1:] {
+			if len(idx.buckets[key]) > len(idx.buckets[canon]) {
+				canon = key
+			}
+		}
+		for _, key := range keys {
+			if key == canon {
+				continue
+			}
+			idx.buckets[canon] = append(idx.buckets[canon], idx.buckets[key]...)
+			delete(idx.buckets, key)
+			delete(idx.key0s, key)
+			idx.aliases[key] = canon
+			merges++
+		}
+	}
+
+	// Keep aliases pointing straight at a live bucket.
+	for key, c := range idx.aliases {
+		if next, ok := idx.aliases[c]; ok {
+			idx.aliases[key] = next
+		}
+	}
+	return merges
+}
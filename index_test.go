@@ -0,0 +1,53 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestIndexCompact(t *testing.T) {
+	idx := tlphone.New().NewIndex()
+	idx.Add("ತುಂಬಾ", "ತುಂಬ", "ತಂಬಾ", "ತಿಂಬಾ", "ಮಕ್ಕಳು", "ಮಕಳು", "ಅನುಗ್ರಹ")
+	if got := idx.Len(); got != 6 {
+		t.Fatalf("Bucket count before compaction: got=%d want=6", got)
+	}
+
+	if got := idx.Compact(); got != 3 {
+		t.Errorf("Merge count mismatch: got=%d want=3", got)
+	}
+	if got := idx.Len(); got != 3 {
+		t.Errorf("Bucket count after compaction: got=%d want=3", got)
+	}
+	if got := idx.Compact(); got != 0 {
+		t.Errorf("Merge count mismatch on compacted index: got=%d want=0", got)
+	}
+
+	// ತುಂಬಾ and ತುಂಬ form the largest bucket and absorb the other two.
+	want := []string{"ತುಂಬಾ", "ತುಂಬ", "ತಂಬಾ", "ತಿಂಬಾ"}
+	for _, w := range want {
+		if got := idx.Lookup(w); !reflect.DeepEqual(got, want) {
+			t.Errorf("Lookup mismatch for '%s': got=%v want=%v", w, got, want)
+		}
+	}
+
+	// ಮಕ್ಕಳು and ಮಕಳು tie, so the smaller key2 (MK2L15) wins.
+	idx.Add("ಮಕಳು")
+	want = []string{"ಮಕ್ಕಳು", "ಮಕಳು", "ಮಕಳು"}
+	if got := idx.Lookup("ಮಕ್ಕಳು"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lookup mismatch for 'ಮಕ್ಕಳು': got=%v want=%v", got, want)
+	}
+
+	// Delimited keys group by the key0 of their words, not a reduction
+	// of the delimited key2.
+	idx = tlphone.New(tlphone.WithCodeDelimiter(true)).NewIndex()
+	idx.Add("ಮಕ್ಕಳು", "ಮಕಳ")
+	if got := idx.Compact(); got != 1 {
+		t.Errorf("Merge count mismatch with delimiter: got=%d want=1", got)
+	}
+	want = []string{"ಮಕಳ", "ಮಕ್ಕಳು"}
+	if got := idx.Lookup("ಮಕಳ"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Lookup mismatch for 'ಮಕಳ' with delimiter: got=%v want=%v", got, want)
+	}
+}
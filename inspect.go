@@ -0,0 +1,241 @@
+package tlphone
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// HasGlyph reports whether glyph is mapped by any of the vowel, consonant,
+// compound or modifier maps of k.
+func (k *TLPhone) HasGlyph(glyph string) bool {
+	for _, m := range []map[string]string{k.vowels, k.consonants, k.compounds, k.modifiers} {
+		if _, ok := m[glyph]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportedGlyphCount returns the number of glyphs in each of the maps of
+// k, options included, for a service to log at startup as a check that
+// the expected rules are loaded.
+func (k *TLPhone) SupportedGlyphCount() (vowels, consonants, compounds, modifiers int) {
+	return len(k.vowels), len(k.consonants), len(k.compounds), len(k.modifiers)
+}
+
+// scriptOf returns the name of the Unicode script of r, or "" for runes of
+// the Common and Inherited pseudo-scripts, such as digits, punctuation and
+// joiners, which belong to no script in particular.
+func scriptOf(r rune) string {
+	if unicode.Is(unicode.Kannada, r) {
+		return "Kannada"
+	}
+	for _, name := range scriptNames {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// scriptNames lists the Unicode scripts in name order, leaving out Common
+// and Inherited.
+var scriptNames = func() []string {
+	var names []string
+	for name := range unicode.Scripts {
+		if name != "Common" && name != "Inherited" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}()
+
+// EncodeWithScript encodes input and also returns the name of the Unicode
+// script most of its runes are written in, such as "Kannada" or
+// "Devanagari", or "" if it has no script runes at all. Ties go to the
+// script named first alphabetically. Callers can use it to route or tag
+// mixed corpora; only Kannada script runes contribute to the keys.
+func (k *TLPhone) EncodeWithScript(input string) (Result, string) {
+	var (
+		counts = make(map[string]int)
+		best   string
+	)
+	for _, r := range input {
+		if s := scriptOf(r); s != "" {
+			counts[s]++
+			if c, b := counts[s], counts[best]; c > b || c == b && s < best {
+				best = s
+			}
+		}
+	}
+	return k.EncodeResult(input), best
+}
+
+// InspectionReport describes the raw input to an encoder, as returned by
+// Inspect.
+type InspectionReport struct {
+	ValidUTF8    bool     // input is valid UTF-8
+	Scripts      []string // Unicode scripts of its runes, in name order
+	Mapped       int      // runes the encoder matched to a glyph
+	Unmapped     int      // other runes, whitespace aside, which it drops
+	HasBidi      bool     // input holds bidirectional formatting characters
+	HasZeroWidth bool     // input holds ZWJ, ZWNJ, ZWSP or a BOM
+}
+
+// Inspect reports on input without encoding it, so ingestion pipelines
+// can decide how to clean or route it first. Each invalid byte counts as
+// one unmapped rune.
+func (k *TLPhone) Inspect(input string) InspectionReport {
+	rep := InspectionReport{ValidUTF8: utf8.ValidString(input)}
+	seen := make(map[string]bool)
+	total := 0
+	for _, r := range input {
+		if s := scriptOf(r); s != "" && !seen[s] {
+			seen[s] = true
+			rep.Scripts = append(rep.Scripts, s)
+		}
+		switch {
+		case isBidi(r):
+			rep.HasBidi = true
+		case isJoiner(r), r == '\u200b', r == '\ufeff':
+			rep.HasZeroWidth = true
+		}
+		if !unicode.IsSpace(r) {
+			total++
+		}
+	}
+	sort.Strings(rep.Scripts)
+
+	_, rep.Mapped = k.mappedRunes(input, cleanStages)
+	rep.Unmapped = total - rep.Mapped
+	return rep
+}
+
+// EncodeReportingDrops encodes input and also returns, in order, the runes
+// of input that did not make it into the keys: the formatting characters
+// and prefix that preparing strips, the runes the script filter strips,
+// signs that normalizing collapses, and those no glyph matched, such as
+// digits or stray signs. Whitespace is not reported.
+func (k *TLPhone) EncodeReportingDrops(input string) (Result, []rune) {
+	var (
+		clean, kept = k.track(input, cleanStages)
+		out         []rune
+		i           int
+	)
+	for _, r := range input {
+		if !kept[i] && !unicode.IsSpace(r) {
+			out = append(out, r)
+		}
+		i++
+	}
+	return k.encode(clean, &EncodeBuffer{}), out
+}
+
+// track runs input through stages, as clean does for cleanStages, and
+// returns the result along with, for each rune of input, whether it
+// reached a glyph the encoder matched.
+func (k *TLPhone) track(input string, stages []stage) (string, []bool) {
+	t := text{s: input, from: make([][]int, utf8.RuneCountInString(input))}
+	for i := range t.from {
+		t.from[i] = []int{i}
+	}
+	var (
+		kept = make([]bool, len(t.from))
+		pos  int // the rune index in t.s of the next glyph
+	)
+	t = k.run(t, stages, true, true)
+	k.walk(t.s, func(glyph, _ string) {
+		for end := pos + utf8.RuneCountInString(glyph); pos < end; pos++ {
+			for _, i := range t.from[pos] {
+				kept[i] = true
+			}
+		}
+	}, func(s string) {
+		pos += utf8.RuneCountInString(s)
+	})
+	return t.s, kept
+}
+
+// maxAmbiguity caps AmbiguityScore so long words cannot overflow it.
+const maxAmbiguity = 1 << 30
+
+// AmbiguityScore estimates how many distinct spellings collapse to the
+// key0 of input: the product, over its codes, of the number of glyphs in
+// the maps whose code has the same key0. Codes that vanish from key0, such
+// as vowel signs, count once. A higher score means more phonetic
+// neighbours; ಹ scores 1, while ಮಕ್ಕಳು scores 48 (2 glyphs for M, 6 for K,
+// 4 for L).
+func (k *TLPhone) AmbiguityScore(input string) int {
+	glyphs := make(map[string]int)
+	for _, m := range []map[string]string{k.vowels, k.consonants, k.compounds, k.modifiers} {
+		for _, code := range m {
+			glyphs[k.key0Of([]Step{{Code: code}})]++
+		}
+	}
+
+	score := 0
+	for _, t := range k.tokens(k.clean(input)) {
+		if score == 0 {
+			score = 1
+		}
+		key := k.key0Of([]Step{{Code: t}})
+		if n := glyphs[key]; key != "" && n > 0 {
+			score *= n
+			if score > maxAmbiguity {
+				return maxAmbiguity
+			}
+		}
+	}
+	return score
+}
+
+// CompoundPattern, ConsonantPattern and VowelPattern return what the
+// scanner matches from the compound, consonant or vowel map of k, options
+// included, as a regular expression: the quoted glyphs joined with "|",
+// longest first and then in byte order, so leftmost-first matching takes
+// the glyph the scanner would. At each position the scanner matches the
+// longest glyph of any map, preferring a compound, then a consonant, vowel
+// and modifier of the same length; glyphs too long for it to try are left
+// out. They are meant for diagnosing why some input did not match.
+func (k *TLPhone) CompoundPattern() string {
+	return k.pattern(k.compounds)
+}
+
+// ConsonantPattern is like CompoundPattern for the consonant map.
+func (k *TLPhone) ConsonantPattern() string {
+	return k.pattern(k.consonants)
+}
+
+// VowelPattern is like CompoundPattern for the vowel map.
+func (k *TLPhone) VowelPattern() string {
+	return k.pattern(k.vowels)
+}
+
+func (k *TLPhone) pattern(m map[string]string) string {
+	var alts []string
+	for _, g := range glyphsOf(m) {
+		if utf8.RuneCountInString(g) <= k.maxRunes {
+			alts = append(alts, regexp.QuoteMeta(g))
+		}
+	}
+	return strings.Join(alts, "|")
+}
+
+// glyphsOf returns the glyphs of m, longest first, then in byte order.
+func glyphsOf(m map[string]string) []string {
+	glyphs := make([]string, 0, len(m))
+	for g := range m {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool {
+		if len(glyphs[i]) != len(glyphs[j]) {
+			return len(glyphs[i]) > len(glyphs[j])
+		}
+		return glyphs[i] < glyphs[j]
+	})
+	return glyphs
+}
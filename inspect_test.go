@@ -0,0 +1,220 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestHasGlyph(t *testing.T) {
+	tests := []struct {
+		glyph  string
+		expect bool
+	}{
+		{"ಕ", true},
+		{"ಅ", true},
+		{"ಕ್ಷ", true},
+		{"ು", true},
+		{"ಽ", false},
+		{"಼", false},
+		{"ಕ಼", false},
+		{"k", false},
+		{"", false},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.HasGlyph(test.glyph); got != test.expect {
+			t.Errorf("HasGlyph mismatch for '%s': got=%v want=%v", test.glyph, got, test.expect)
+		}
+	}
+	if !tlphone.New(tlphone.WithLoanwords(true)).HasGlyph("ಕ಼") {
+		t.Errorf("HasGlyph mismatch for 'ಕ಼' with loanwords: got=false want=true")
+	}
+}
+
+func TestSupportedGlyphCount(t *testing.T) {
+	tests := []struct {
+		opts                                     []tlphone.Option
+		vowels, consonants, compounds, modifiers int
+	}{
+		{nil, 13, 36, 22, 15},
+		{[]tlphone.Option{tlphone.WithLoanwords(true)}, 13, 40, 22, 15},
+	}
+
+	for _, test := range tests {
+		v, c, cp, m := tlphone.New(test.opts...).SupportedGlyphCount()
+		if v != test.vowels || c != test.consonants || cp != test.compounds || m != test.modifiers {
+			t.Errorf("SupportedGlyphCount mismatch: got=%d,%d,%d,%d want=%d,%d,%d,%d",
+				v, c, cp, m, test.vowels, test.consonants, test.compounds, test.modifiers)
+		}
+	}
+}
+
+func TestEncodeWithScript(t *testing.T) {
+	tests := []struct {
+		input  string
+		script string
+	}{
+		{"ಮಕ್ಕಳು", "Kannada"},
+		{"नमस्ते", "Devanagari"},
+		{"ಮಕ್ಕಳು नमस्ते", "Devanagari"},
+		{"ಮಕ್ಕಳು नमस", "Kannada"},
+		{"hello", "Latin"},
+		{"123 !?", ""},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		r, script := p.EncodeWithScript(test.input)
+		if script != test.script {
+			t.Errorf("Script mismatch for input '%s': got=%s want=%s", test.input, script, test.script)
+		}
+		if want := p.EncodeResult(test.input); r != want {
+			t.Errorf("Result mismatch for input '%s': got=%v want=%v", test.input, r, want)
+		}
+	}
+}
+
+func TestAmbiguityScore(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect int
+	}{
+		{"ಹ", 1},
+		{"ಹಯ", 2},
+		{"ಮಕ್ಕಳು", 48},
+		{"", 0},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.AmbiguityScore(test.input); got != test.expect {
+			t.Errorf("AmbiguityScore mismatch for input '%s': got=%d want=%d", test.input, got, test.expect)
+		}
+	}
+	if a, b := p.AmbiguityScore("ಹಯ"), p.AmbiguityScore("ಗಡ"); a >= b {
+		t.Errorf("AmbiguityScore of distinctive 'ಹಯ' (%d) not below ambiguous 'ಗಡ' (%d)", a, b)
+	}
+
+	// With T folding the ತ and ಟ series share a key0.
+	fold := tlphone.New(tlphone.WithFoldTSeries(true))
+	if a, b := fold.AmbiguityScore("ತ"), p.AmbiguityScore("ತ")+p.AmbiguityScore("ಟ"); a != b {
+		t.Errorf("AmbiguityScore mismatch for input 'ತ' with T folding: got=%d want=%d", a, b)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	tests := []struct {
+		input  string
+		opts   []tlphone.Option
+		expect tlphone.InspectionReport
+	}{
+		{"ಮಕ್ಕಳು", nil, tlphone.InspectionReport{
+			ValidUTF8: true,
+			Scripts:   []string{"Kannada"},
+			Mapped:    6,
+		}},
+		{"\u200fಮಕ್ಕ\u200dಳು abc\xff", nil, tlphone.InspectionReport{
+			Scripts:      []string{"Kannada", "Latin"},
+			Mapped:       6,
+			Unmapped:     6,
+			HasBidi:      true,
+			HasZeroWidth: true,
+		}},
+		{"नमस्ते\ufeff", nil, tlphone.InspectionReport{
+			ValidUTF8:    true,
+			Scripts:      []string{"Devanagari"},
+			Unmapped:     7,
+			HasZeroWidth: true,
+		}},
+		{"ತುಂಂಬಾ", []tlphone.Option{tlphone.WithDedupeModifiers(true)}, tlphone.InspectionReport{
+			ValidUTF8: true,
+			Scripts:   []string{"Kannada"},
+			Mapped:    5,
+			Unmapped:  1,
+		}},
+		{"", nil, tlphone.InspectionReport{ValidUTF8: true}},
+	}
+
+	for _, test := range tests {
+		p := tlphone.New(test.opts...)
+		if got := p.Inspect(test.input); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("Inspect mismatch for input '%s': got=%+v want=%+v", test.input, got, test.expect)
+		}
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	p := tlphone.New(tlphone.WithLoanwords(true))
+	if got := p.ConsonantPattern(); !strings.Contains(got, "ಕ|") {
+		t.Errorf("ConsonantPattern is missing 'ಕ': got=%s", got)
+	}
+	if got := p.ConsonantPattern(); !strings.Contains(got, "ಜ಼") {
+		t.Errorf("ConsonantPattern is missing loanword 'ಜ಼': got=%s", got)
+	}
+
+	tests := []struct {
+		pattern string
+		input   string
+		expect  string
+	}{
+		{p.ConsonantPattern(), "ಮಕಾ", "ಮ"},
+		{p.ConsonantPattern(), "ಜ಼ಮೀನು", "ಜ಼"},
+		{p.CompoundPattern(), "ಮಗ್ಗಾ", "ಗ್ಗಾ"},
+		{p.CompoundPattern(), "ಮಕ್ಕಿ", "ಕ್ಕ"},
+		{p.VowelPattern(), "ಕಅಂದ", "ಅ"},
+	}
+	for _, test := range tests {
+		re, err := regexp.Compile(test.pattern)
+		if err != nil {
+			t.Fatalf("Pattern does not compile: %v", err)
+		}
+		if got := re.FindString(test.input); got != test.expect {
+			t.Errorf("Pattern match mismatch for input '%s': got=%s want=%s", test.input, got, test.expect)
+		}
+	}
+}
+
+func TestEncodeReportingDrops(t *testing.T) {
+	tests := []struct {
+		input  string
+		opts   []tlphone.Option
+		expect []rune
+	}{
+		{"ಮಕ್ಕಳು", nil, nil},
+		{"ಮಕ್ಕಳುabc೧ ನೀರು!", nil, []rune{'a', 'b', 'c', '೧', '!'}},
+		{"नमस्ते ಮಳೆ", nil, []rune("नमस्ते")},
+		{"ಜ಼ಮೀನು", nil, []rune{'಼'}},
+		{"ಜ಼ಮೀನು", []tlphone.Option{tlphone.WithLoanwords(true)}, nil},
+		{"ಕುxುುು", []tlphone.Option{tlphone.WithMaxModifiersPerBase(3)}, []rune{'x', 'ು'}},
+		{"Tulu ಮಳೆ", []tlphone.Option{tlphone.WithLatinFallback(true)}, nil},
+		{"ಮ\xffಳೆ", nil, []rune{utf8.RuneError}},
+		{"ಕಂಂ", []tlphone.Option{tlphone.WithDedupeModifiers(true)}, []rune{'ಂ'}},
+		{"ನೝ", []tlphone.Option{tlphone.WithLegacyOrthography(true)}, nil},
+		{"ನೝ", nil, []rune{'ೝ'}},
+		{"ಶ್ರೀ ರಾಮ", []tlphone.Option{tlphone.WithStripPrefixes("ಶ್ರೀ")}, []rune("ಶ್ರೀ")},
+		{"ಕ್\u200dಕ", nil, []rune{'\u200d'}},
+		{"\u200fಮಕ್ಕಳು\u200e", nil, []rune{'\u200f', '\u200e'}},
+		{"ಕ\u0c82\u0cbf", []tlphone.Option{tlphone.WithMaxModifiersPerBase(1)}, []rune{'\u0c82'}},
+		{"ಕ\u0cc6\u0cc2", nil, nil},
+		{"ತು೦ಬಾ", []tlphone.Option{tlphone.WithHomoglyphFolding(true)}, nil},
+		{"ಕ&amp;ಮ", []tlphone.Option{tlphone.WithUnescapeHTML(true)}, []rune("&amp;")},
+		{"&#3221;ಮ&lt;", []tlphone.Option{tlphone.WithUnescapeHTML(true)}, []rune("&lt;")},
+	}
+
+	for _, test := range tests {
+		p := tlphone.New(test.opts...)
+		r, got := p.EncodeReportingDrops(test.input)
+		if !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("Dropped runes mismatch for input '%s': got=%q want=%q", test.input, got, test.expect)
+		}
+		if want := p.EncodeResult(test.input); r != want {
+			t.Errorf("Result mismatch for input '%s': got=%v want=%v", test.input, r, want)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package tlphone
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Option configures a TLPhone created with New.
+type Option func(*TLPhone)
+
+// WithStripPrefixes removes a leading title or honorific, such as "ಶ್ರೀ",
+// from the trimmed input before encoding, so "ಶ್ರೀ ರಾಮ" and "ರಾಮ" get the
+// same keys. A prefix is only stripped where its word ends, at whitespace or
+// punctuation such as the dot of "ಶ್ರೀ.", so ಶ್ರೀನಿವಾಸ keeps its ಶ್ರೀ.
+// Prefixes are tried in the given order and at most one is stripped. Input
+// consisting of nothing but a prefix is left alone.
+func WithStripPrefixes(prefixes ...string) Option {
+	return func(k *TLPhone) {
+		k.stripPrefixes = append(k.stripPrefixes, prefixes...)
+	}
+}
+
+func stripPrefix(k *TLPhone, t text) text {
+	for _, p := range k.stripPrefixes {
+		if p == "" || len(t.s) <= len(p) || !strings.HasPrefix(t.s, p) {
+			continue
+		}
+		if r, _ := utf8.DecodeRuneInString(t.s[len(p):]); unicode.IsSpace(r) || unicode.IsPunct(r) {
+			rest := strings.TrimLeftFunc(t.s[len(p):], unicode.IsSpace)
+			i := len(t.s) - len(rest)
+			return t.cut(i, i+len(strings.TrimRightFunc(rest, unicode.IsSpace)))
+		}
+	}
+	return t
+}
+
+// WithLatinFallback keeps runs of ASCII letters, which are otherwise
+// stripped as non-Kannada, and encodes each run as its uppercased letters.
+// Explain reports the original run, case intact, as the glyph.
+func WithLatinFallback(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.latin = enabled
+	}
+}
+
+// WithStripEmoji treats emoji and other Unicode symbols as word separators
+// and removes them ahead of the script filter, so social media text such
+// as "ತುಂಬಾ😀ಮಕ್ಕಳು" splits into its words in EncodeWords.
+func WithStripEmoji(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.stripEmoji = enabled
+	}
+}
+
+// WithLoanwords maps the nukta-bearing consonants of borrowed sounds, which
+// are ಕ಼ (Q), ಖ಼ (X), ಜ಼ (G) and ಫ಼ (W), to dedicated codes, so a loanword
+// like ಜ಼ಮೀನು keeps its /z/ instead of encoding like ಜಮೀನು.
+func WithLoanwords(enabled bool) Option {
+	return func(k *TLPhone) {
+		if !enabled {
+			return
+		}
+		for g, c := range loanwords {
+			k.consonants[g] = c
+		}
+	}
+}
+
+// WithSpaceCode keeps spaces inside the input, which are otherwise dropped,
+// and encodes each run of them as code, marking the boundary of a compound
+// written in parts. The code should not contain digits, as the reductions
+// to key1 and key0 would strip them, nor collide with the glyph codes; "_"
+// works well.
+func WithSpaceCode(code string) Option {
+	return func(k *TLPhone) {
+		k.spaceCode = code
+	}
+}
+
+// WithSuffixes replaces the inflectional suffixes StemKeys strips. Longer
+// suffixes should come before shorter ones they end with.
+func WithSuffixes(suffixes ...string) Option {
+	return func(k *TLPhone) {
+		k.suffixes = append([]string{}, suffixes...)
+	}
+}
+
+// WithFinalDevoicing folds a voiced stop at the very end of a word, one
+// closed by a virama as in ಕಬ್, to its voiceless counterpart in key0, as
+// dialects that devoice final stops pronounce it: B becomes P, D becomes 0
+// and J becomes C. K, T and 0 already cover both voicings.
+func WithFinalDevoicing(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.finalDevoicing = enabled
+	}
+}
+
+// WithDedupeModifiers collapses a modifier typed twice in a row, such as a
+// doubled anusvara or virama, into one before encoding.
+func WithDedupeModifiers(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.dedupeMods = enabled
+	}
+}
+
+// WithRephaCode encodes a repha, ರ with a virama in front of a consonant as
+// in ಕರ್ಮ, as code instead of the R of a full ರ. As with WithSpaceCode,
+// the code should be kept clear of digits and of the glyph codes.
+func WithRephaCode(code string) Option {
+	return func(k *TLPhone) {
+		k.rephaCode = code
+	}
+}
+
+// WithAvagrahaAsLength reads an avagraha (ಽ) as lengthening the vowel before
+// it, as some transcriptions use it, instead of dropping it. The avagraha
+// encodes as LengthCode whatever the vowel, so ಬಾಽ encodes as B: and ಬೀಽ as
+// B4:. Like the vowel signs, the length is dropped from key1 and key0.
+func WithAvagrahaAsLength(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.avagrahaLength = enabled
+	}
+}
+
+// WithCodeDelimiter separates the code tokens of key2 with CodeDelimiter,
+// so downstream tools can split it unambiguously: plain concatenation
+// writes ಐ and ಅಇ both as AI, and ಞ and ನಜ both as NJ, while delimited they
+// come out as AI and A.I, NJ and N.J. Key1 and key0 are not delimited.
+func WithCodeDelimiter(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.delimit = enabled
+	}
+}
+
+// WithLegacyOrthography rewrites letters of older orthography to their
+// modern replacements before encoding, so historic texts match modern
+// spellings: ಱ becomes ರ, ೞ becomes ಳ, the nakaara pollu ೝ becomes ನ್, and
+// the jihvamuliya ೱ and upadhmaniya ೲ become the visarga ಃ.
+func WithLegacyOrthography(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.legacy = enabled
+	}
+}
+
+// WithMaxModifiersPerBase ignores all but the first n modifiers, such as
+// vowel signs and the anusvara, that follow one base glyph, so input
+// stacking many combining marks cannot blow up the keys. The default is
+// 3; n of 0 or less removes the limit.
+func WithMaxModifiersPerBase(n int) Option {
+	return func(k *TLPhone) {
+		k.maxMods = n
+	}
+}
+
+// WithFoldTSeries merges the dental ತ series, coded 0, into the retroflex
+// ಟ series, coded T, at key0 for the loosest matching, so ಕಟ್ಟು and ಕತ್ತು
+// share key0. Key1 and key2 keep the two apart.
+func WithFoldTSeries(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.foldT = enabled
+	}
+}
+
+// WithUnescapeHTML decodes HTML entities and numeric character references,
+// such as &#3221; or &#x0C95; for ಕ, before encoding, so text scraped from
+// web pages encodes like the characters it stands for.
+func WithUnescapeHTML(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.unescapeHTML = enabled
+	}
+}
+
+// WithHomoglyphFolding replaces spellings that look like another glyph
+// with that glyph before encoding, undoing typos that are invisible on
+// screen. The pairs are ಅ+ಾ for ಆ, ಇ+ೕ for ಈ, ಉ+ಾ for ಊ, ಒ+ೌ for ಔ, and the
+// digit zero ೦ for the anusvara ಂ.
+func WithHomoglyphFolding(enabled bool) Option {
+	return func(k *TLPhone) {
+		k.homoglyphs = enabled
+	}
+}
+
+// WithKeyTransform passes each key the Encode functions return through fn
+// once the standard reductions and folds are done, with level 0, 1 or 2
+// telling which key it is, for final tweaks such as prefixing a namespace.
+// Functions that compare or derive keys internally, such as Similarity and
+// AllVariantKeys, work on the keys before the transform.
+func WithKeyTransform(fn func(level int, key string) string) Option {
+	return func(k *TLPhone) {
+		k.keyTransform = fn
+	}
+}
@@ -0,0 +1,463 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestWithStripPrefixes(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"ಶ್ರೀ ರಾಮ", "ರಾಮ"},
+		{"ಶ್ರೀ. ರಾಮ", "ರಾಮ"},
+		{"ಶ್ರೀರಾಮ", "ಶ್ರೀರಾಮ"},
+		{"ಶ್ರೀನಿವಾಸ", "ಶ್ರೀನಿವಾಸ"},
+		{"ಶ್ರೀಮತಿ ಲಕ್ಷ್ಮಿ", "ಲಕ್ಷ್ಮಿ"},
+		{"ರಾಮ ಶ್ರೀ", "ರಾಮ ಶ್ರೀ"},
+		{"ಶ್ರೀ", "ಶ್ರೀ"},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithStripPrefixes("ಶ್ರೀಮತಿ", "ಶ್ರೀ"))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		got := p.EncodeResult(test.input)
+		want := plain.EncodeResult(test.expect)
+		if got != want {
+			t.Errorf("Prefix mismatch for input '%s': got=%v want=%v", test.input, got, want)
+		}
+	}
+
+	if got, want := plain.EncodeResult("ಶ್ರೀ ರಾಮ"), plain.EncodeResult("ರಾಮ"); got == want {
+		t.Errorf("Prefix stripped without option: got=%v", got)
+	}
+}
+
+func TestWithStripEmoji(t *testing.T) {
+	var (
+		input = "ತುಂಬಾ😀ಮಕ್ಕಳು 🙏🏽#ವೃತ್ತಿ ❤️"
+		p     = tlphone.New(tlphone.WithStripEmoji(true))
+		plain = tlphone.New()
+		want  = []tlphone.Result{plain.EncodeResult("ತುಂಬಾ"), plain.EncodeResult("ಮಕ್ಕಳು"), plain.EncodeResult("ವೃತ್ತಿ")}
+	)
+	if got := p.EncodeWords(input); !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeWords mismatch with emoji stripping: got=%v want=%v", got, want)
+	}
+	if got := plain.EncodeWords(input); len(got) != 2 {
+		t.Errorf("EncodeWords without emoji stripping: got=%v, want 2 words", got)
+	}
+}
+
+func TestWithLoanwords(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectKey2  string
+		expectPlain string
+	}{
+		{"ಜ಼ಮೀನು", "GM4N5", "JM4N5"},
+		{"ಖ಼ಾನ್", "XN", "KN"},
+		{"ಕ಼ಿಸ್ಸಾ", "Q4S", "K4S"},
+		{"ಫ಼ೈಲ್", "W7L", "F7L"},
+		{"ಮಕ್ಕಳು", "MK2L15", "MK2L15"},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithLoanwords(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		if got := p.EncodeResult(test.input).Key2; got != test.expectKey2 {
+			t.Errorf("Key2 mismatch for input '%s': got=%s want=%s", test.input, got, test.expectKey2)
+		}
+		if got := plain.EncodeResult(test.input).Key2; got != test.expectPlain {
+			t.Errorf("Key2 mismatch without loanwords for input '%s': got=%s want=%s", test.input, got, test.expectPlain)
+		}
+	}
+
+	// A loanword consonant must not share its keys with a native glyph.
+	for _, pair := range [][2]string{{"ಜ಼ಳ", "ೞಳ"}, {"ಫ಼ೈಲ್", "ಫೈಲ್"}} {
+		a, b := p.EncodeResult(pair[0]), p.EncodeResult(pair[1])
+		if a.Key0 == b.Key0 || a.Key1 == b.Key1 || a.Key2 == b.Key2 {
+			t.Errorf("Keys collide for inputs '%s' and '%s': got=%v and %v", pair[0], pair[1], a, b)
+		}
+	}
+}
+
+func TestWouldChange(t *testing.T) {
+	tests := []struct {
+		input  string
+		opts   []tlphone.Option
+		expect bool
+	}{
+		{"ಜ಼ಮೀನು", []tlphone.Option{tlphone.WithLoanwords(true)}, true},
+		{"ಮಕ್ಕಳು", []tlphone.Option{tlphone.WithLoanwords(true)}, false},
+		{"ಶ್ರೀ ರಾಮ", []tlphone.Option{tlphone.WithStripPrefixes("ಶ್ರೀ")}, true},
+		{"ರಾಮ", []tlphone.Option{tlphone.WithStripPrefixes("ಶ್ರೀ")}, false},
+		{"ಜ಼ಮೀನು", nil, false},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.WouldChange(test.input, test.opts...); got != test.expect {
+			t.Errorf("WouldChange mismatch for input '%s': got=%v want=%v", test.input, got, test.expect)
+		}
+	}
+	if got := p.EncodeResult("ಜ಼ಮೀನು").Key2; got != "JM4N5" {
+		t.Errorf("WouldChange modified the encoder: got=%s want=JM4N5", got)
+	}
+}
+
+func TestWithSpaceCode(t *testing.T) {
+	var (
+		p     = tlphone.New(tlphone.WithSpaceCode("_"))
+		plain = tlphone.New()
+	)
+	tests := []struct {
+		input       string
+		expectKey2  string
+		expectPlain string
+	}{
+		{"ಬಂಗಾರ ಬಳೆ", "B3KR_BL16", "B3KRBL16"},
+		{"ಬಂಗಾರ  -\tಬಳೆ", "B3KR_BL16", "B3KRBL16"},
+		{" ಬಂಗಾರಬಳೆ ", "B3KRBL16", "B3KRBL16"},
+		{"ಮಕ್ ಕಳು", "MK_KL15", "MK2L15"},
+	}
+	for _, test := range tests {
+		if got := p.EncodeResult(test.input).Key2; got != test.expectKey2 {
+			t.Errorf("Key2 mismatch for input '%s': got=%s want=%s", test.input, got, test.expectKey2)
+		}
+		if got := plain.EncodeResult(test.input).Key2; got != test.expectPlain {
+			t.Errorf("Key2 mismatch without space code for input '%s': got=%s want=%s", test.input, got, test.expectPlain)
+		}
+	}
+	if got := p.EncodeResult("ಬಂಗಾರ ಬಳೆ").Key0; got != "B3KR_BL" {
+		t.Errorf("Key0 mismatch for input 'ಬಂಗಾರ ಬಳೆ': got=%s want=B3KR_BL", got)
+	}
+}
+
+func TestWithFinalDevoicing(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectKey0  string
+		expectPlain string
+	}{
+		{"ಕಬ್", "KP", "KB"},
+		{"ಮದ್ದ್", "M0", "MD"},
+		{"ರಾಜ್", "RC", "RJ"},
+		{"ಕಬ", "KB", "KB"},
+		{"ತುಂಬಾ", "03B", "03B"},
+		{"ಬಕ್", "BK", "BK"},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithFinalDevoicing(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		r := p.EncodeResult(test.input)
+		if r.Key0 != test.expectKey0 {
+			t.Errorf("Key0 mismatch for input '%s': got=%s want=%s", test.input, r.Key0, test.expectKey0)
+		}
+		if want := plain.EncodeResult(test.input); r.Key1 != want.Key1 || r.Key2 != want.Key2 {
+			t.Errorf("Key1/Key2 changed by devoicing for input '%s': got=%v want=%v", test.input, r, want)
+		}
+		if got := plain.EncodeResult(test.input).Key0; got != test.expectPlain {
+			t.Errorf("Key0 mismatch without devoicing for input '%s': got=%s want=%s", test.input, got, test.expectPlain)
+		}
+	}
+}
+
+func TestWithDedupeModifiers(t *testing.T) {
+	tests := []struct {
+		input string
+		clean string
+	}{
+		{"ತುಂಂಬಾ", "ತುಂಬಾ"},
+		{"ತುುಂಬಾಾ", "ತುಂಬಾ"},
+		{"ಮಕ್್ಕಳು", "ಮಕ್ಕಳು"},
+		{"ಮಕ್ಕಳುು", "ಮಕ್ಕಳು"},
+		{"ಮಕ್ಕಳು", "ಮಕ್ಕಳು"},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithDedupeModifiers(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		if got, want := p.EncodeResult(test.input), plain.EncodeResult(test.clean); got != want {
+			t.Errorf("Dedupe mismatch for input '%s': got=%v want=%v", test.input, got, want)
+		}
+	}
+	if got, want := plain.EncodeResult("ತುಂಂಬಾ"), plain.EncodeResult("ತುಂಬಾ"); got == want {
+		t.Errorf("Modifiers deduped without option: got=%v", got)
+	}
+	// Repeated base glyphs are left alone.
+	if got, want := p.EncodeResult("ಮಮ").Key2, "MM"; got != want {
+		t.Errorf("Key2 mismatch for input 'ಮಮ': got=%s want=%s", got, want)
+	}
+}
+
+func TestWithRephaCode(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectKey2  string
+		expectPlain string
+	}{
+		{"ಕರ್ಮ", "KWM", "KRM"},
+		{"ಸೂರ್ಯ", "S5WY", "S5RY"},
+		{"ಧರ್ಮಸ್ಥಳ", "0WMS0L1", "0RMS0L1"},
+		{"ಕರ", "KR", "KR"},
+		{"ಬಾರ್", "BR", "BR"},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithRephaCode("W"))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		if got := p.EncodeResult(test.input).Key2; got != test.expectKey2 {
+			t.Errorf("Key2 mismatch for input '%s': got=%s want=%s", test.input, got, test.expectKey2)
+		}
+		if got := plain.EncodeResult(test.input).Key2; got != test.expectPlain {
+			t.Errorf("Key2 mismatch without repha code for input '%s': got=%s want=%s", test.input, got, test.expectPlain)
+		}
+	}
+}
+
+func TestWithAvagrahaAsLength(t *testing.T) {
+	tests := []struct {
+		input       string
+		expectKey2  string
+		expectPlain string
+	}{
+		{"ಬಾಽ", "B:", "B"},
+		{"ಬೀಽ", "B4:", "B4"},
+		{"ಅಽಮ", "A:M", "AM"},
+		{"ಕಂಽ", "K3:", "K3"},
+		{"ಕ್ಽ", "K", "K"},
+		{"ಽಕ", "K", "K"},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithAvagrahaAsLength(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		if got := p.EncodeResult(test.input).Key2; got != test.expectKey2 {
+			t.Errorf("Key2 mismatch for input '%s': got=%s want=%s", test.input, got, test.expectKey2)
+		}
+		if got := plain.EncodeResult(test.input).Key2; got != test.expectPlain {
+			t.Errorf("Key2 mismatch without avagraha length for input '%s': got=%s want=%s", test.input, got, test.expectPlain)
+		}
+	}
+
+	// The length is marked the same way for every vowel, and only key2
+	// keeps it.
+	a, b := p.EncodeResult("ಬಾಽ"), p.EncodeResult("ಬೀಽ")
+	if !strings.HasSuffix(a.Key2, string(tlphone.LengthCode)) || !strings.HasSuffix(b.Key2, string(tlphone.LengthCode)) {
+		t.Errorf("Length code missing for inputs 'ಬಾಽ' and 'ಬೀಽ': got=%s and %s", a.Key2, b.Key2)
+	}
+	if a.Key1 != plain.EncodeResult("ಬಾ").Key1 || b.Key1 != plain.EncodeResult("ಬೀ").Key1 {
+		t.Errorf("Length kept in key1 for inputs 'ಬಾಽ' and 'ಬೀಽ': got=%s and %s", a.Key1, b.Key1)
+	}
+}
+
+func TestWithCodeDelimiter(t *testing.T) {
+	tests := []struct {
+		input      string
+		expectKey2 string
+	}{
+		{"ಐ", "AI"},
+		{"ಅಇ", "A.I"},
+		{"ಞ", "NJ"},
+		{"ನಜ", "N.J"},
+		{"ಮಕ್ಕಳು", "M.K2.L1.5"},
+		{"ಕಣ್ಣ್", "K.N12"},
+		{"", ""},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithCodeDelimiter(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		got, want := p.EncodeResult(test.input), plain.EncodeResult(test.input)
+		if got.Key2 != test.expectKey2 {
+			t.Errorf("Key2 mismatch for input '%s': got=%s want=%s", test.input, got.Key2, test.expectKey2)
+		}
+		if got.Key0 != want.Key0 || got.Key1 != want.Key1 {
+			t.Errorf("Key0/Key1 changed by delimiter for input '%s': got=%v want=%v", test.input, got, want)
+		}
+	}
+	if a, b := plain.EncodeResult("ಐ").Key2, plain.EncodeResult("ಅಇ").Key2; a != b {
+		t.Errorf("Undelimited keys expected to collide: got=%s and %s", a, b)
+	}
+}
+
+func TestWithLegacyOrthography(t *testing.T) {
+	tests := []struct {
+		legacy string
+		modern string
+	}{
+		{"ಮೞೆ", "ಮಳೆ"},
+		{"ಕಱು", "ಕರು"},
+		{"ಬಾೞ್", "ಬಾಳ್"},
+		{"ಅವೝ", "ಅವನ್"},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithLegacyOrthography(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		if got, want := p.EncodeResult(test.legacy), plain.EncodeResult(test.modern); got != want {
+			t.Errorf("Legacy mismatch for input '%s': got=%v want=%v", test.legacy, got, want)
+		}
+		if got, want := plain.EncodeResult(test.legacy), plain.EncodeResult(test.modern); got == want {
+			t.Errorf("Legacy input '%s' matched modern form without option: got=%v", test.legacy, got)
+		}
+	}
+}
+
+func TestWithMaxModifiersPerBase(t *testing.T) {
+	spam := "ಕ" + strings.Repeat("ು", 50)
+
+	if got, want := tlphone.New().EncodeResult(spam).Key2, "K555"; got != want {
+		t.Errorf("Key2 mismatch for 50 stacked marks: got=%s want=%s", got, want)
+	}
+	if got, want := tlphone.New(tlphone.WithMaxModifiersPerBase(1)).EncodeResult(spam+"ಮಿಿ").Key2, "K5M4"; got != want {
+		t.Errorf("Key2 mismatch for 50 stacked marks with limit 1: got=%s want=%s", got, want)
+	}
+	if got, want := len(tlphone.New(tlphone.WithMaxModifiersPerBase(0)).EncodeResult(spam).Key2), 51; got != want {
+		t.Errorf("Key2 length mismatch for 50 stacked marks without limit: got=%d want=%d", got, want)
+	}
+}
+
+func TestWithFoldTSeries(t *testing.T) {
+	tests := []struct {
+		retroflex string
+		dental    string
+	}{
+		{"ಕಟ್ಟು", "ಕತ್ತು"},
+		{"ಪಾಠ", "ಪಾತ"},
+		{"ಬಡ", "ಬದ"},
+		{"ಕೋಟೆ", "ಕೋತೆ"},
+	}
+
+	var (
+		fold  = tlphone.New(tlphone.WithFoldTSeries(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		r, d := fold.EncodeResult(test.retroflex), fold.EncodeResult(test.dental)
+		if r.Key0 != d.Key0 {
+			t.Errorf("Key0 mismatch for '%s' and '%s' with fold: got=%s and %s", test.retroflex, test.dental, r.Key0, d.Key0)
+		}
+		pr, pd := plain.EncodeResult(test.retroflex), plain.EncodeResult(test.dental)
+		if pr.Key0 == pd.Key0 {
+			t.Errorf("Key0 match for '%s' and '%s' without fold: got=%s", test.retroflex, test.dental, pr.Key0)
+		}
+		if r.Key1 != pr.Key1 || d.Key1 != pd.Key1 {
+			t.Errorf("Key1 changed for '%s' and '%s' with fold: got=%s and %s", test.retroflex, test.dental, r.Key1, d.Key1)
+		}
+	}
+}
+
+func TestWithUnescapeHTML(t *testing.T) {
+	tests := []struct {
+		escaped string
+		literal string
+	}{
+		{"&#3246;&#3221;&#3277;&#3221;&#3251;&#3265;", "ಮಕ್ಕಳು"},
+		{"&#x0CA8;&#x0CC0;&#x0CB0;&#x0CC1;", "ನೀರು"},
+		{"&nbsp;ಮ&#3251;ೆ&amp;", "ಮಳೆ"},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithUnescapeHTML(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		if got, want := p.EncodeResult(test.escaped), plain.EncodeResult(test.literal); got != want {
+			t.Errorf("Unescaped mismatch for input '%s': got=%v want=%v", test.escaped, got, want)
+		}
+	}
+	if got := plain.EncodeResult(tests[0].escaped).Key2; got != "" {
+		t.Errorf("Entities decoded without option: got=%s", got)
+	}
+}
+
+func TestWithHomoglyphFolding(t *testing.T) {
+	tests := []struct {
+		confused  string
+		canonical string
+	}{
+		{"ತು೦ಬಾ", "ತುಂಬಾ"},
+		{"ಒೌಷಧ", "ಔಷಧ"},
+		{"ಬ೦ಗಾರ", "ಬಂಗಾರ"},
+	}
+
+	var (
+		fold  = tlphone.New(tlphone.WithHomoglyphFolding(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		if got, want := fold.EncodeResult(test.confused), plain.EncodeResult(test.canonical); got != want {
+			t.Errorf("Folded mismatch for input %+q: got=%v want=%v", test.confused, got, want)
+		}
+		if got, want := plain.EncodeResult(test.confused), plain.EncodeResult(test.canonical); got == want {
+			t.Errorf("Homoglyph input %+q matched without folding: got=%v", test.confused, got)
+		}
+	}
+	if got, want := fold.EncodeResult("ಅಾನೆ"), plain.EncodeResult("ಆನೆ"); got != want {
+		t.Errorf("Folded mismatch for input 'ಅಾನೆ': got=%v want=%v", got, want)
+	}
+
+	// The digit ೦ of a number is not an anusvara.
+	for _, input := range []string{"೧೦೦", "ಶಾಲೆ ೧೦", "ತುಂಬಾ ೦"} {
+		if got, want := fold.EncodeResult(input), plain.EncodeResult(input); got != want {
+			t.Errorf("Folded digit for input '%s': got=%v want=%v", input, got, want)
+		}
+	}
+}
+
+func TestWithKeyTransform(t *testing.T) {
+	var (
+		plain = tlphone.New()
+		p     = tlphone.New(tlphone.WithKeyTransform(func(level int, key string) string {
+			if level == 0 {
+				return "tl:" + key
+			}
+			return key
+		}))
+	)
+	for _, test := range encodeTests {
+		got, want := p.EncodeResult(test.input), plain.EncodeResult(test.input)
+		if got.Key0 != "tl:"+want.Key0 {
+			t.Errorf("Key0 mismatch for input '%s': got=%s want=tl:%s", test.input, got.Key0, want.Key0)
+		}
+		if got.Key1 != want.Key1 || got.Key2 != want.Key2 {
+			t.Errorf("Key1/Key2 changed by transform for input '%s': got=%v want=%v", test.input, got, want)
+		}
+	}
+
+	levels := map[int]bool{}
+	tlphone.New(tlphone.WithKeyTransform(func(level int, key string) string {
+		levels[level] = true
+		return key
+	})).EncodeResult("ಮಕ್ಕಳು")
+	if !reflect.DeepEqual(levels, map[int]bool{0: true, 1: true, 2: true}) {
+		t.Errorf("Transform levels mismatch: got=%v", levels)
+	}
+
+	tagged := tlphone.New(tlphone.WithKeyTransform(func(_ int, key string) string { return "#" + key }))
+	if r, toks := tagged.EncodeTokenized("ಮಕ್ಕಳು"); r.Key2 != "#MK2L15" || !reflect.DeepEqual(toks, []string{"M", "K2", "L1", "5"}) {
+		t.Errorf("EncodeTokenized mismatch with transform: got=%v %v", r, toks)
+	}
+}
@@ -0,0 +1,25 @@
+package tlphone
+
+// WindowKeys slides a window of windowWords words across text, split as by
+// EncodeWords, and returns the phrase key of each window: the keys of its
+// words concatenated in order. Encoding a query phrase the same way finds
+// it anywhere in the text. It returns nil if text has fewer words than the
+// window.
+func (k *TLPhone) WindowKeys(text string, windowWords int) []Result {
+	words := k.EncodeWords(text)
+	if windowWords < 1 || windowWords > len(words) {
+		return nil
+	}
+
+	out := make([]Result, 0, len(words)-windowWords+1)
+	for i := 0; i+windowWords <= len(words); i++ {
+		var r Result
+		for _, w := range words[i : i+windowWords] {
+			r.Key0 += w.Key0
+			r.Key1 += w.Key1
+			r.Key2 += w.Key2
+		}
+		out = append(out, r)
+	}
+	return out
+}
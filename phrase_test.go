@@ -0,0 +1,41 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestWindowKeys(t *testing.T) {
+	var (
+		p    = tlphone.New()
+		text = "ಮಕ್ಕಳು ತುಂಬಾ ಬಂಗಾರಾ, ವೃತ್ತಿ"
+	)
+	tests := []struct {
+		n      int
+		expect []tlphone.Result
+	}{
+		{2, []tlphone.Result{
+			{Key0: "MKL03B", Key1: "MKL103B", Key2: "MK2L15053B"},
+			{Key0: "03BB3KR", Key1: "03BB3KR", Key2: "053BB3KR"},
+			{Key0: "B3KRVR0", Key1: "B3KRVR0", Key2: "B3KRVR04"},
+		}},
+		{4, []tlphone.Result{
+			{Key0: "MKL03BB3KRVR0", Key1: "MKL103BB3KRVR0", Key2: "MK2L15053BB3KRVR04"},
+		}},
+		{5, nil},
+		{0, nil},
+	}
+	for _, test := range tests {
+		if got := p.WindowKeys(text, test.n); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("WindowKeys mismatch for %d words: got=%v want=%v", test.n, got, test.expect)
+		}
+	}
+
+	// A phrase query matches a window of the text.
+	query := p.WindowKeys("ತುಂಬ ಬಂಗಾರ", 2)[0]
+	if got := p.WindowKeys(text, 2)[1]; got.Key0 != query.Key0 {
+		t.Errorf("Phrase key0 mismatch: got=%s want=%s", got.Key0, query.Key0)
+	}
+}
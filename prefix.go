@@ -0,0 +1,58 @@
+package tlphone
+
+import "strings"
+
+// EncodePrefix encodes input as the start of a word still being typed. A
+// consonant cluster left dangling at the end, as in ಮಕ್ on the way to
+// ಮಕ್ಕಳು, is left out, because the glyph it completes to may encode
+// differently; so the keys of a prefix are the keys of the full word up to
+// its last complete syllable.
+func (k *TLPhone) EncodePrefix(input string) Result {
+	return k.encodePrefix(k.clean(input), &EncodeBuffer{})
+}
+
+func (k *TLPhone) encodePrefix(input string, buf *EncodeBuffer) Result {
+	for strings.HasSuffix(input, virama) {
+		c := clusters(input)
+		input = input[:len(input)-len(c[len(c)-1])]
+	}
+	return k.encode(input, buf)
+}
+
+// PrefixKeys returns the EncodePrefix keys of every prefix of input that
+// ends on a grapheme cluster boundary, shortest first, for indexing a word
+// for phonetic autocomplete. A cluster is a letter with the signs that
+// follow it, its virama included, so ಮಕ್ಕಳು has the four prefixes ಮ, ಮಕ್,
+// ಮಕ್ಕ and ಮಕ್ಕಳು.
+func (k *TLPhone) PrefixKeys(input string) []Result {
+	var (
+		buf EncodeBuffer
+		out []Result
+		n   int
+	)
+	input = k.clean(input)
+	for _, c := range clusters(input) {
+		n += len(c)
+		out = append(out, k.encodePrefix(input[:n], &buf))
+	}
+	return out
+}
+
+// clusters splits input into grapheme clusters: each rune that is not a
+// combining mark, together with the marks after it.
+func clusters(input string) []string {
+	var (
+		out   []string
+		start int
+	)
+	for i, r := range input {
+		if i > start && markRank(r) < 0 {
+			out = append(out, input[start:i])
+			start = i
+		}
+	}
+	if start < len(input) {
+		out = append(out, input[start:])
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package tlphone_test
+
+import (
+	"strings"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestEncodePrefix(t *testing.T) {
+	tests := []struct {
+		input      string
+		expectKey2 string
+	}{
+		{"ಮಕ್", "M"},
+		{"ಮಕ್ಕ", "MK2"},
+		{"ಸ್ತ್", ""},
+		{"ಕಣ್ಣ್", "K"},
+		{"ಕರ್", "K"},
+		{"ಮಕ್ಕಳು", "MK2L15"},
+	}
+
+	p := tlphone.New(tlphone.WithRephaCode("_"))
+	for _, test := range tests {
+		if got := p.EncodePrefix(test.input).Key2; got != test.expectKey2 {
+			t.Errorf("Key2 mismatch for prefix '%s': got=%s want=%s", test.input, got, test.expectKey2)
+		}
+	}
+}
+
+func TestPrefixKeys(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []string
+	}{
+		{"ಮಕ್ಕಳು", []string{"M", "M", "MK2", "MK2L15"}},
+		{"ಕರ್ಮ", []string{"K", "K", "K_M"}},
+		{"ಬಾಗಿಲು", []string{"B", "BK4", "BK4L5"}},
+		{"", nil},
+	}
+
+	p := tlphone.New(tlphone.WithRephaCode("_"))
+	for _, test := range tests {
+		got := p.PrefixKeys(test.input)
+		if len(got) != len(test.expect) {
+			t.Errorf("Prefix count mismatch for input '%s': got=%d want=%d", test.input, len(got), len(test.expect))
+			continue
+		}
+		for i, r := range got {
+			if r.Key2 != test.expect[i] {
+				t.Errorf("Key2 mismatch for prefix %d of '%s': got=%s want=%s", i, test.input, r.Key2, test.expect[i])
+			}
+			if full := p.EncodeResult(test.input).Key2; !strings.HasPrefix(full, r.Key2) {
+				t.Errorf("Prefix key %s of '%s' is not a prefix of its key %s", r.Key2, test.input, full)
+			}
+		}
+	}
+}
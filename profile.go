@@ -0,0 +1,53 @@
+package tlphone
+
+import "sync"
+
+// ProfilingEncoder encodes like the TLPhone it wraps and counts how often
+// each glyph of the maps is matched, showing which rules real traffic
+// exercises and which are dead. It is safe for concurrent use.
+type ProfilingEncoder struct {
+	k *TLPhone
+
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+// NewProfilingEncoder returns a ProfilingEncoder for k with an empty
+// profile.
+func (k *TLPhone) NewProfilingEncoder() *ProfilingEncoder {
+	return &ProfilingEncoder{k: k, counts: make(map[string]int)}
+}
+
+// EncodeResult is like TLPhone.EncodeResult and adds the glyphs of input
+// to the profile.
+func (p *ProfilingEncoder) EncodeResult(input string) Result {
+	var (
+		clean = p.k.clean(input)
+		seen  = make(map[string]int)
+	)
+	p.k.scan(clean, func(glyph, _ string) {
+		if p.k.HasGlyph(glyph) {
+			seen[glyph]++
+		}
+	})
+
+	p.mu.Lock()
+	for g, n := range seen {
+		p.counts[g] += n
+	}
+	p.mu.Unlock()
+	return p.k.encode(clean, &EncodeBuffer{})
+}
+
+// Profile returns how many times each glyph has been matched so far.
+// Glyphs that never matched are left out; Latin runs, whitespace and other
+// input not from the maps is not counted.
+func (p *ProfilingEncoder) Profile() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make(map[string]int, len(p.counts))
+	for g, n := range p.counts {
+		out[g] = n
+	}
+	return out
+}
@@ -0,0 +1,37 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestProfilingEncoder(t *testing.T) {
+	var (
+		p     = tlphone.New(tlphone.WithLatinFallback(true))
+		enc   = p.NewProfilingEncoder()
+		words = []string{"ಮಕ್ಕಳು", "ಕಣ್ಣು", "ಮಳೆ", "abc"}
+		want  = map[string]int{
+			"ಮ": 20, "ಕ್ಕ": 10, "ಳ": 20, "ು": 20, "ಕ": 10, "ಣ್ಣ": 10, "ೆ": 10,
+		}
+		wg sync.WaitGroup
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, w := range words {
+				if got, want := enc.EncodeResult(w), p.EncodeResult(w); got != want {
+					t.Errorf("Result mismatch for input '%s': got=%v want=%v", w, got, want)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := enc.Profile(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Profile mismatch: got=%v want=%v", got, want)
+	}
+}
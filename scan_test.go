@@ -0,0 +1,115 @@
+package tlphone
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// regexEncoder is the encoder scan replaced: it rewrites glyphs to codes
+// with one regexp and strings.ReplaceAll pass per map. It is kept here
+// only as a reference for the scanner. It took the glyphs in map order, so
+// overlapping glyphs such as ಗ್ಗಾ encoded differently from run to run; here
+// they come longest first, the order the scanner settles on.
+type regexEncoder struct {
+	modCompounds  *regexp.Regexp
+	modConsonants *regexp.Regexp
+	modVowels     *regexp.Regexp
+}
+
+func newRegexEncoder() *regexEncoder {
+	mods := strings.Join(glyphsOf(modifiers), "|")
+	build := func(glyphs map[string]string) *regexp.Regexp {
+		return regexp.MustCompile(`((` + strings.Join(glyphsOf(glyphs), "|") + `)(` + mods + `))`)
+	}
+	return &regexEncoder{
+		modCompounds:  build(compounds),
+		modConsonants: build(consonants),
+		modVowels:     build(vowels),
+	}
+}
+
+func (e *regexEncoder) process(input string) string {
+	input = e.replaceModifiedGlyphs(input, compounds, e.modCompounds)
+	for _, ck := range glyphsOf(compounds) {
+		input = strings.ReplaceAll(input, ck, `{`+compounds[ck]+`}`)
+	}
+	input = e.replaceModifiedGlyphs(input, consonants, e.modConsonants)
+	input = e.replaceModifiedGlyphs(input, vowels, e.modVowels)
+	for _, ck := range glyphsOf(consonants) {
+		input = strings.ReplaceAll(input, ck, `{`+consonants[ck]+`}`)
+	}
+	for _, vk := range glyphsOf(vowels) {
+		input = strings.ReplaceAll(input, vk, `{`+vowels[vk]+`}`)
+	}
+	for _, mk := range glyphsOf(modifiers) {
+		input = strings.ReplaceAll(input, mk, modifiers[mk])
+	}
+	return regexp.MustCompile(`[^0-9A-Z]`).ReplaceAllString(input, "")
+}
+
+func (e *regexEncoder) replaceModifiedGlyphs(input string, glyphs map[string]string, r *regexp.Regexp) string {
+	for _, matches := range r.FindAllStringSubmatch(input, -1) {
+		for _, m := range matches {
+			if rep, ok := glyphs[m]; ok {
+				input = strings.ReplaceAll(input, m, rep)
+			}
+		}
+	}
+	return input
+}
+
+// randomWord builds a word of well-formed aksharas: a vowel or consonant,
+// an optional virama and second consonant, then up to two signs. Only the
+// last akshara may end in a virama, since a virama before the next base
+// forms conjuncts across aksharas, which the regex encoder resolved in map
+// order.
+func randomWord(rnd *rand.Rand, bases, joins, signs []string) string {
+	var b strings.Builder
+	n := 1 + rnd.Intn(5)
+	for i := 0; i < n; i++ {
+		b.WriteString(bases[rnd.Intn(len(bases))])
+		if rnd.Intn(3) == 0 {
+			b.WriteString(virama)
+			b.WriteString(joins[rnd.Intn(len(joins))])
+		}
+		for m := rnd.Intn(3); m > 0; m-- {
+			b.WriteString(signs[rnd.Intn(len(signs))])
+		}
+	}
+	if rnd.Intn(4) == 0 {
+		b.WriteString(virama)
+	}
+	return b.String()
+}
+
+func TestScanMatchesRegexEncoder(t *testing.T) {
+	var (
+		bases = append(glyphsOf(vowels), glyphsOf(consonants)...)
+		joins = glyphsOf(consonants)
+		signs []string
+	)
+	for _, m := range glyphsOf(modifiers) {
+		if m != virama {
+			signs = append(signs, m)
+		}
+	}
+
+	var (
+		e   = newRegexEncoder()
+		k   = New()
+		rnd = rand.New(rand.NewSource(1))
+	)
+	for i := 0; i < 20000; i++ {
+		word := randomWord(rnd, bases, joins, signs)
+
+		var b strings.Builder
+		k.scan(word, func(_, code string) {
+			b.WriteString(code)
+		})
+		if got, want := b.String(), e.process(word); got != want {
+			t.Fatalf("Key2 mismatch for input '%s': got=%s want=%s", word, got, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package tlphone
+
+import "strings"
+
+// defaultSuffixes are common Tulu plural and case endings, longest first so
+// the longer of two overlapping endings is tried before the shorter one.
+var defaultSuffixes = []string{
+	"ಕುಲು", // plural of human nouns
+	"ಡ್ದ್", // ablative
+	"ಲು",   // plural
+	"ಳು",   // plural, in Kannada influenced spelling
+	"ಡ್",   // locative
+	"ಟ್",   // locative
+	"ಗ್",   // dative
+	"ನ್",   // accusative
+	"ದ",    // genitive
+	"ನ",    // genitive
+}
+
+// suffixList returns the suffixes set with WithSuffixes, or the defaults.
+func (k *TLPhone) suffixList() []string {
+	if k.suffixes != nil {
+		return k.suffixes
+	}
+	return defaultSuffixes
+}
+
+// stems returns input, cleaned, followed by every form of it with one of
+// the suffixes stripped. A suffix is only stripped if a base remains that
+// does not end in a virama, so ಮದ್ದ keeps its ದ.
+func (k *TLPhone) stems(input string) []string {
+	input = k.clean(input)
+	out := []string{input}
+	for _, suf := range k.suffixList() {
+		base := strings.TrimSuffix(input, suf)
+		if suf == "" || base == input || base == "" || strings.HasSuffix(base, virama) {
+			continue
+		}
+		out = append(out, base)
+	}
+	return out
+}
+
+// StemKeys returns the distinct key0s of input and of its forms with a
+// common inflectional suffix stripped, the input's own key first. Indexing
+// and querying these matches words across inflections without a
+// morphological analyser. The suffixes can be changed with WithSuffixes.
+func (k *TLPhone) StemKeys(input string) []string {
+	var (
+		out  []string
+		seen = make(map[string]bool)
+		buf  EncodeBuffer
+	)
+	for _, s := range k.stems(input) {
+		key := k.encode(s, &buf).Key0
+		if key != "" && !seen[key] {
+			seen[key] = true
+			out = append(out, key)
+		}
+	}
+	return out
+}
+
+// BaseFormKey returns the key0 of input with the first of the suffixes of
+// StemKeys that applies stripped, or of input itself if none does, so that
+// a plural such as ಮನೆಕುಲು and its singular ಮನೆ share a key. The default
+// suffixes are tried longest first; WithSuffixes changes them.
+func (k *TLPhone) BaseFormKey(input string) string {
+	base := k.stems(input)
+	if len(base) > 1 {
+		base = base[1:]
+	}
+	return k.encode(base[0], &EncodeBuffer{}).Key0
+}
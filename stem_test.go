@@ -0,0 +1,65 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestStemKeys(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []string
+	}{
+		{"ಇಲ್ಲಡ್", []string{"ILT", "IL"}},
+		{"ಇಲ್ಲ", []string{"IL"}},
+		{"ಮನೆಕುಲು", []string{"MNKL", "MN", "MNK"}},
+		{"ಮದ್ದ", []string{"MD"}},
+		{"", nil},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.StemKeys(test.input); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("StemKeys mismatch for input '%s': got=%v want=%v", test.input, got, test.expect)
+		}
+	}
+
+	inflected, stem := p.StemKeys("ಇಲ್ಲಡ್"), p.StemKeys("ಇಲ್ಲ")
+	if !contains(inflected, stem[0]) {
+		t.Errorf("StemKeys of inflected form %v missing stem key %s", inflected, stem[0])
+	}
+}
+
+func TestWithSuffixes(t *testing.T) {
+	p := tlphone.New(tlphone.WithSuffixes("ಡ್"))
+	if got, want := p.StemKeys("ಮನೆಕುಲು"), []string{"MNKL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("StemKeys mismatch with custom suffixes: got=%v want=%v", got, want)
+	}
+}
+
+func TestBaseFormKey(t *testing.T) {
+	tests := []struct {
+		plural   string
+		singular string
+		expect   string
+	}{
+		{"ಮನೆಕುಲು", "ಮನೆ", "MN"},
+		{"ಮಕ್ಕಳು", "ಮಕ್ಕ", "MK"},
+		{"ಆನೆಲು", "ಆನೆ", "AN"},
+		{"ಇಲ್ಲಡ್", "ಇಲ್ಲ", "IL"},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		for _, input := range []string{test.plural, test.singular} {
+			if got := p.BaseFormKey(input); got != test.expect {
+				t.Errorf("BaseFormKey mismatch for input '%s': got=%s want=%s", input, got, test.expect)
+			}
+		}
+	}
+	if got := p.BaseFormKey(""); got != "" {
+		t.Errorf("BaseFormKey mismatch for empty input: got=%s want=", got)
+	}
+}
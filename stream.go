@@ -0,0 +1,64 @@
+package tlphone
+
+import (
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// largeChunk is the size in bytes EncodeLargeToken lets its input grow to
+// before it looks for a place to encode and let go of what it has read.
+const largeChunk = 4096
+
+// EncodeLargeToken is like EncodeResult for input read from r, such as a
+// single pathologically long token, that should not be held in memory
+// whole. The runes are cleaned and scanned in chunks of a few kilobytes,
+// cut only in front of a consonant or vowel that starts a new glyph, so
+// the keys equal those EncodeResult returns for the whole input. Only the
+// keys grow with the input. Errors from r other than io.EOF are returned
+// with an empty Result.
+func (k *TLPhone) EncodeLargeToken(r io.RuneReader) (Result, error) {
+	var (
+		buf   EncodeBuffer
+		chunk []byte
+		enc   [utf8.UTFMax]byte
+		prev  rune
+		head  = true
+		tail  string // the cleaned last chunk
+	)
+	flush := func(last bool) {
+		tail = k.cleanPart(string(chunk), head, last)
+		k.appendCodes(tail, &buf)
+		chunk, head = chunk[:0], false
+	}
+	for {
+		c, _, err := r.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Result{}, err
+		}
+		if len(chunk) >= largeChunk && startsGlyph(prev, c) {
+			flush(false)
+		}
+		n := utf8.EncodeRune(enc[:], c)
+		chunk = append(chunk, enc[:n]...)
+		prev = c
+	}
+	flush(true)
+	return k.finishKeys(&buf, strings.HasSuffix(tail, virama)), nil
+}
+
+// startsGlyph reports whether r, following prev, is a Kannada consonant or
+// vowel letter that no glyph in the maps continues into. Nothing cleaning
+// does reaches across such a point either.
+func startsGlyph(prev, r rune) bool {
+	if r < '\u0c85' || r > '\u0cb9' {
+		return false
+	}
+	// A virama joins r into a conjunct or repha, and the nakaara pollu
+	// becomes one with WithLegacyOrthography.
+	return unicode.Is(unicode.Kannada, prev) && prev != '\u0ccd' && prev != '\u0cdd'
+}
@@ -0,0 +1,63 @@
+package tlphone_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestEncodeLargeToken(t *testing.T) {
+	var (
+		word  = "ಮಕ್ಕಳು\u200dಕ್ಷೇತ್ರಅಂಽಕರ್ಮೊೞೝಕ"
+		large = strings.Repeat(word, 2000) + "ಕ್"
+	)
+	tests := []struct {
+		name  string
+		input string
+		opts  []tlphone.Option
+	}{
+		{"default", large, nil},
+		{"options", "  ಶ್ರೀ " + large + " ", []tlphone.Option{
+			tlphone.WithStripPrefixes("ಶ್ರೀ"),
+			tlphone.WithLegacyOrthography(true),
+			tlphone.WithAvagrahaAsLength(true),
+			tlphone.WithRephaCode("_"),
+			tlphone.WithCodeDelimiter(true),
+			tlphone.WithFinalDevoicing(true),
+		}},
+		{"spaced", strings.Repeat("ಮಕ್ಕಳು abc ಕಣ್ಣು ", 1000), []tlphone.Option{
+			tlphone.WithSpaceCode("_"),
+			tlphone.WithLatinFallback(true),
+			tlphone.WithDedupeModifiers(true),
+		}},
+		{"empty", "", nil},
+	}
+
+	for _, test := range tests {
+		p := tlphone.New(test.opts...)
+		got, err := p.EncodeLargeToken(strings.NewReader(test.input))
+		if err != nil {
+			t.Fatalf("EncodeLargeToken failed for %s input: %v", test.name, err)
+		}
+		if want := p.EncodeResult(test.input); got != want {
+			t.Errorf("EncodeLargeToken mismatch for %s input: got=%.40v... want=%.40v...", test.name, got, want)
+		}
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r failingReader) ReadRune() (rune, int, error) { return 0, 0, r.err }
+
+func TestEncodeLargeTokenError(t *testing.T) {
+	want := errors.New("read failed")
+	if _, err := tlphone.New().EncodeLargeToken(failingReader{want}); err != want {
+		t.Errorf("Error mismatch: got=%v want=%v", err, want)
+	}
+	if _, err := tlphone.New().EncodeLargeToken(failingReader{io.EOF}); err != nil {
+		t.Errorf("Error mismatch for io.EOF: got=%v want=<nil>", err)
+	}
+}
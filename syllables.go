@@ -0,0 +1,201 @@
+package tlphone
+
+import "strings"
+
+// Signs that follow the vowel nucleus of a syllable rather than forming it.
+const (
+	anusvara = "ಂ"
+	visarga  = "ಃ"
+)
+
+// syllables splits input into orthographic syllables (aksharas): a vowel,
+// or a consonant with any consonants joined to it by a virama, together
+// with the signs that follow it. ಮಕ್ಕಳು splits into ಮ, ಕ್ಕ and ಳು.
+func (k *TLPhone) syllables(input string) [][]Step {
+	var (
+		out  [][]Step
+		prev string
+	)
+	k.scan(k.clean(input), func(glyph, code string) {
+		_, isMod := k.modifiers[glyph]
+		if len(out) == 0 || !isMod && prev != virama {
+			out = append(out, nil)
+		}
+		out[len(out)-1] = append(out[len(out)-1], Step{Glyph: glyph, Code: code})
+		prev = glyph
+	})
+	return out
+}
+
+// nucleusEnd returns the number of steps of syllable s up to and including
+// its vowel nucleus, leaving out a trailing anusvara or visarga.
+func nucleusEnd(s []Step) int {
+	n := len(s)
+	for n > 1 && (s[n-1].Glyph == anusvara || s[n-1].Glyph == visarga) {
+		n--
+	}
+	return n
+}
+
+// InitialSyllableKey returns the key0 of the first syllable of input, up to
+// and including its vowel nucleus. Words that alliterate share it.
+func (k *TLPhone) InitialSyllableKey(input string) string {
+	syl := k.syllables(input)
+	if len(syl) == 0 {
+		return ""
+	}
+
+	return k.key0Of(syl[0][:nucleusEnd(syl[0])])
+}
+
+// finalSyllableKey returns the rhyme of input: the last vowel nucleus,
+// with its length, followed by the key0 of what closes the word after it,
+// an anusvara or visarga and any vowelless consonants, as in ಣ್ಣ್. The
+// consonants before the nucleus do not count.
+func (k *TLPhone) finalSyllableKey(input string) string {
+	syl := k.syllables(input)
+	if len(syl) == 0 {
+		return ""
+	}
+	i := len(syl) - 1
+	for i > 0 && syl[i][len(syl[i])-1].Glyph == virama {
+		i--
+	}
+
+	var (
+		s    = syl[i]
+		n    = nucleusEnd(s)
+		b    []byte
+		coda []Step
+	)
+	if nucleus := s[n-1]; nucleus.Glyph != virama {
+		// A consonant ends in the inherent vowel ಅ. Vowel letters and signs
+		// have different codes, so both go by the vowel they write.
+		switch q, ok := vowelQualities[nucleus.Glyph]; {
+		case ok:
+			b = append(b, q...)
+		case k.classOf(nucleus.Glyph) == ClassModifier:
+			b = append(b, nucleus.Code...)
+		}
+		if len(b) == 0 {
+			b = append(b, 'A')
+		}
+		if longVowels[nucleus.Glyph] {
+			b = append(b, LengthCode)
+		}
+	} else {
+		n = 0
+	}
+	coda = append(coda, s[n:]...)
+	for _, s := range syl[i+1:] {
+		coda = append(coda, s...)
+	}
+	return string(b) + k.key0Of(coda)
+}
+
+// Rhymes reports whether a and b end in the same rhyme: the same final
+// vowel, long or short, closed by consonants and signs that share their
+// key0. ಮಕ್ಕಳು rhymes with ಬಾಳು but not with ತುಂಬಾ, and ಕಬಾ not with ಮಬು.
+func (k *TLPhone) Rhymes(a, b string) bool {
+	ka := k.finalSyllableKey(a)
+	return ka != "" && ka == k.finalSyllableKey(b)
+}
+
+// AlliterationGroups groups words by the key0 of their initial consonant,
+// the first consonant of the syllable InitialSyllableKey covers. Words
+// starting with a vowel are grouped together under the empty key; words
+// with no codes at all are left out.
+func (k *TLPhone) AlliterationGroups(words []string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, w := range words {
+		syl := k.syllables(w)
+		if len(syl) == 0 {
+			continue
+		}
+		key := ""
+		if first := syl[0][0]; k.classOf(first.Glyph) != ClassVowel {
+			// In a word like ಬ್ the consonant ends the word, so its virama
+			// goes with it for WithFinalDevoicing.
+			n := 1
+			if len(syl) == 1 && len(syl[0]) == 2 && syl[0][1].Glyph == virama {
+				n = 2
+			}
+			key = k.key0Of(syl[0][:n])
+		}
+		groups[key] = append(groups[key], w)
+	}
+	return groups
+}
+
+// longVowels are the vowels and vowel signs that spell a long vowel or a
+// diphthong.
+var longVowels = map[string]bool{
+	"ಆ": true, "ಈ": true, "ಊ": true, "ಏ": true, "ಐ": true, "ಓ": true, "ಔ": true,
+	"ಾ": true, "ೀ": true, "ೂ": true, "ೇ": true, "ೈ": true, "ೋ": true, "ೌ": true,
+}
+
+// vowelQualities gives the vowel each vowel letter and vowel sign writes,
+// leaving its length to longVowels, so the sign in ನೀ has the quality of
+// the letter ಈ.
+var vowelQualities = map[string]string{
+	"ಅ": "A", "ಆ": "A", "ಾ": "A",
+	"ಇ": "I", "ಈ": "I", "ಿ": "I", "ೀ": "I",
+	"ಉ": "U", "ಊ": "U", "ು": "U", "ೂ": "U",
+	"ಋ": "R", "ೠ": "R", "ೃ": "R", "ೄ": "R",
+	"ಎ": "E", "ಏ": "E", "ೆ": "E", "ೇ": "E",
+	"ಐ": "AI", "ೈ": "AI",
+	"ಒ": "O", "ಓ": "O", "ೊ": "O", "ೋ": "O",
+	"ಔ": "AU", "ೌ": "AU",
+}
+
+// ProsodyKey returns a key of the rhythm of input, leaving out which
+// consonants and vowels it has: for each syllable, 2 if it starts with a
+// doubled consonant, then S for a short vowel or L for a long one, and N
+// for an anusvara. Syllables without a vowel, as in a final ನ್, add only
+// their gemination marker. ಮಕ್ಕಳು gives S2SS and ಬಾಗಿಲು LSS.
+func (k *TLPhone) ProsodyKey(input string) string {
+	var b []byte
+	for _, syl := range k.syllables(input) {
+		if geminated(syl) {
+			b = append(b, geminationMark)
+		}
+		switch last := syl[nucleusEnd(syl)-1]; {
+		case last.Glyph == virama:
+		case longVowels[last.Glyph]:
+			b = append(b, 'L')
+		default:
+			b = append(b, 'S')
+		}
+		for _, s := range syl[nucleusEnd(syl):] {
+			if s.Glyph == anusvara {
+				b = append(b, 'N')
+			}
+		}
+	}
+	return string(b)
+}
+
+// undoubled returns the single consonant of a doubled consonant glyph such
+// as ಕ್ಕ, and whether glyph is one.
+func undoubled(glyph string) (string, bool) {
+	i := strings.Index(glyph, virama)
+	if i > 0 && glyph[:i] == glyph[i+len(virama):] {
+		return glyph[:i], true
+	}
+	return "", false
+}
+
+// geminated reports whether syllable s holds a doubled consonant, either
+// as one glyph such as ಕ್ಕ or as a consonant, virama and the consonant
+// again.
+func geminated(s []Step) bool {
+	for i, st := range s {
+		if _, ok := undoubled(st.Glyph); ok {
+			return true
+		}
+		if i+2 < len(s) && s[i+1].Glyph == virama && s[i+2].Glyph == st.Glyph {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestInitialSyllableKey(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"ತುಂಬಾ", "0"},
+		{"ತುಳು", "0"},
+		{"ಮಕ್ಕಳು", "M"},
+		{"ಮಂಗಳೂರು", "M"},
+		{"ಅನುಗ್ರಹ", "A"},
+		{"ಕ್ರಮ", "KR"},
+		{"ಕ್ಷೇತ್ರ", "KS"},
+		{"", ""},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.InitialSyllableKey(test.input); got != test.expect {
+			t.Errorf("Initial syllable key mismatch for input '%s': got=%s want=%s", test.input, got, test.expect)
+		}
+	}
+
+	if a, b := p.InitialSyllableKey("ತುಂಬಾ"), p.InitialSyllableKey("ತುಳು"); a != b {
+		t.Errorf("Initial syllable key mismatch for same onset: got=%s and %s", a, b)
+	}
+	if a, b := p.InitialSyllableKey("ತುಂಬಾ"), p.InitialSyllableKey("ಮಕ್ಕಳು"); a == b {
+		t.Errorf("Initial syllable key match for different onsets: got=%s", a)
+	}
+
+	// The key is a key0 under every option that shapes key0.
+	fold := tlphone.New(tlphone.WithFoldTSeries(true))
+	if a, b := fold.InitialSyllableKey("ತಲೆ"), fold.InitialSyllableKey("ಟಲೆ"); a != b || a != "T" {
+		t.Errorf("Initial syllable key mismatch with T folding: got=%s and %s want=T", a, b)
+	}
+	if got := tlphone.New(tlphone.WithFinalDevoicing(true)).InitialSyllableKey("ಬ್"); got != "P" {
+		t.Errorf("Initial syllable key mismatch with devoicing for input 'ಬ್': got=%s want=P", got)
+	}
+}
+
+func TestRhymes(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect bool
+	}{
+		{"ಮಕ್ಕಳು", "ಬಾಳು", true},
+		{"ತುಂಬಾ", "ಅಂಬಾ", true},
+		{"ಬಣ್ಣ", "ಸುಣ್ಣ", true},
+		{"ಮಕ್ಕಳು", "ತುಂಬಾ", false},
+		{"ಕಣ್ಣ್", "ಕಣ", false},
+		{"ಕಬಾ", "ಮಬು", false},
+		{"ಕಬಾ", "ತುಂಬಾ", true},
+		{"ಕಿ", "ಕೀ", false},
+		{"ಈ", "ನೀ", true},
+		{"ಊ", "ಪೂ", true},
+		{"ಇ", "ಕಿ", true},
+		{"ಓ", "ಬೋ", true},
+		{"ಅ", "ಕ", true},
+		{"ಇ", "ಕೀ", false},
+		{"ಎ", "ಕೊ", false},
+		{"ಬಲಂ", "ಕಲಂ", true},
+		{"ಬಲಂ", "ಬಲ", false},
+		{"", "", false},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.Rhymes(test.a, test.b); got != test.expect {
+			t.Errorf("Rhymes mismatch for '%s' and '%s': got=%v want=%v", test.a, test.b, got, test.expect)
+		}
+	}
+}
+
+func TestAlliterationGroups(t *testing.T) {
+	p := tlphone.New()
+	got := p.AlliterationGroups([]string{"ಮಕ್ಕಳು", "ತುಂಬಾ", "ಮಂಗಳೂರು", "ಅನುಗ್ರಹ", "ದಾರಿ", "ಕ್ರಮ", "ಕಾಡು", "ಇಲ್ಲ", "abc"})
+	want := map[string][]string{
+		"M": {"ಮಕ್ಕಳು", "ಮಂಗಳೂರು"},
+		"0": {"ತುಂಬಾ", "ದಾರಿ"},
+		"K": {"ಕ್ರಮ", "ಕಾಡು"},
+		"":  {"ಅನುಗ್ರಹ", "ಇಲ್ಲ"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AlliterationGroups mismatch: got=%v want=%v", got, want)
+	}
+}
+
+func TestProsodyKey(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect string
+	}{
+		{"ಮಕ್ಕಳು", "S2SS"},
+		{"ಮಕಳು", "SSS"},
+		{"ಬಾಗಿಲು", "LSS"},
+		{"ಕಣ್ಣ್", "S2"},
+		{"ತುಂಬಾ", "SNL"},
+		{"ಅಮ್ಮ", "S2S"},
+		{"ಅಗ್\u200dಗ", "S2S"},
+		{"", ""},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.ProsodyKey(test.input); got != test.expect {
+			t.Errorf("ProsodyKey mismatch for input '%s': got=%s want=%s", test.input, got, test.expect)
+		}
+	}
+}
@@ -4,8 +4,9 @@
 package tlphone
 
 import (
-	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 var vowels = map[string]string{
@@ -24,11 +25,33 @@ var consonants = map[string]string{
 	"ಳ": "L1", "ೞ": "Z", "ಱ": "R1",
 }
 
+// devoiced maps the codes of voiced stops to their voiceless counterparts
+// for WithFinalDevoicing. The other stop codes already cover both voicings.
+var devoiced = map[string]byte{
+	"B": 'P', // ಬ ಭ ಬ್ಬ
+	"D": '0', // ದ್ದ ದ್ಧ
+	"J": 'C', // ಜ ಝ ಜ್ಜ
+}
+
+// loanwords are the nukta-bearing consonants written for borrowed sounds,
+// enabled by WithLoanwords. Without it the nukta is dropped and they encode
+// like their base consonant. Z and F already stand for ೞ and ಫ, so ಜ಼ and ಫ಼
+// take the otherwise unused G and W.
+var loanwords = map[string]string{
+	"ಕ಼": "Q", // /q/, as in ಕ಼ಿಸ್ಸಾ
+	"ಖ಼": "X", // /x/, as in ಖ಼ಾನ್
+	"ಜ಼": "G", // /z/, as in ಜ಼ಮೀನು
+	"ಫ಼": "W", // /f/, as in ಫ಼ೈಲ್
+}
+
+// Geminate nasals follow ಲ್ಲ and ಳ್ಳ: the gemination marker 2 is appended
+// to the code of the single nasal, so ನ್ನ (N2) and ಣ್ಣ (N12) both reduce
+// to N at key0, while key1 keeps the retroflex N1 apart from N.
 var compounds = map[string]string{
 	"ಕ್ಕ": "K2", "ಗ್ಗಾ": "K", "ಙ್ಙ": "NG",
 	"ಚ್ಚ": "C2", "ಜ್ಜ": "J", "ಞ್ಞ": "NJ",
-	"ಟ್ಟ": "T2", "ಣ್ಣ": "N2",
-	"ತ್ತ": "0", "ದ್ದ": "D", "ದ್ಧ": "D", "ನ್ನ": "NN",
+	"ಟ್ಟ": "T2", "ಣ್ಣ": "N12",
+	"ತ್ತ": "0", "ದ್ದ": "D", "ದ್ಧ": "D", "ನ್ನ": "N2",
 	"ಬ್ಬ": "B", "ಪ್ಪ": "P2", "ಮ್ಮ": "M2",
 	"ಯ್ಯ": "Y", "ಲ್ಲ": "L2", "ವ್ವ": "V",
 	"ಶ್ಶ": "S1", "ಸ್ಸ": "S", "ಳ್ಳ": "L12", "ಕ್ಷ": "KS1",
@@ -37,89 +60,589 @@ var compounds = map[string]string{
 var modifiers = map[string]string{
 	"ಾ": "", "ಃ": "", "್": "", "ೃ": "R",
 	"ಂ": "3", "ಿ": "4", "ೀ": "4", "ು": "5", "ೂ": "5", "ೆ": "6",
-	"ೇ": "6", "ೈ": "7", "ೊ": "8", "ೋ": "8", "ೌ": "9", "ൗ": "9",
+	"ೇ": "6", "ೈ": "7", "ೊ": "8", "ೋ": "8", "ೌ": "9",
 }
 
-var (
-	regexKey0     = regexp.MustCompile(`[1,2,4-9]`)
-	regexKey1     = regexp.MustCompile(`[2,4-9]`)
-	regexNonTulu  = regexp.MustCompile(`[\P{Kannada}]`)
-	regexAlphaNum = regexp.MustCompile(`[^0-9A-Z]`)
+// dentalT is the code of the dental ತ series, which WithFoldTSeries merges
+// into the T of the retroflex ಟ series at key0.
+const dentalT = '0'
+
+// Codes dropped from key2 to derive key1 and key0: the gemination marker,
+// vowel signs and vowel length go first, then the retroflex/sibilant
+// marker.
+const (
+	key1Drop = "2456789" + string(LengthCode)
+	key0Drop = "12456789" + string(LengthCode)
 )
 
+// LengthCode marks a lengthened vowel in key2 under WithAvagrahaAsLength.
+const LengthCode = ':'
+
+// CodeDelimiter separates the code tokens of key2 under WithCodeDelimiter.
+const CodeDelimiter = '.'
+
+const virama = "್"
+
+// avagraha usually marks an elided vowel and is dropped, but can be read as
+// a length mark with WithAvagrahaAsLength.
+const avagraha = "ಽ"
+
+// repha is the half-form of ರ that sits above the consonant after it.
+const repha = "ರ್"
+
+// maxGlyphRunes caps the length in runes of the glyphs the encoder matches.
+const maxGlyphRunes = 8
+
+// Result holds the three phonetic keys of an input, from the loosest
+// (Key0) to the most specific (Key2).
+type Result struct {
+	Key0 string
+	Key1 string
+	Key2 string
+}
+
 type TLPhone struct {
-	modCompounds  *regexp.Regexp
-	modConsonants *regexp.Regexp
-	modVowels     *regexp.Regexp
+	vowels     map[string]string
+	consonants map[string]string
+	compounds  map[string]string
+	modifiers  map[string]string
+	// maxRunes is the length in runes of the longest glyph in the maps.
+	maxRunes int
+
+	stripPrefixes  []string
+	latin          bool
+	stripEmoji     bool
+	spaceCode      string
+	suffixes       []string
+	finalDevoicing bool
+	dedupeMods     bool
+	rephaCode      string
+	avagrahaLength bool
+	delimit        bool
+	legacy         bool
+	maxMods        int
+	foldT          bool
+	unescapeHTML   bool
+	homoglyphs     bool
+	keyTransform   func(level int, key string) string
+}
+
+// defaultMaxMods is the default for WithMaxModifiersPerBase. Real text
+// needs at most a vowel sign and an anusvara or visarga on one base.
+const defaultMaxMods = 3
+
+func New(opts ...Option) *TLPhone {
+	tl := &TLPhone{
+		vowels:     copyMap(vowels),
+		consonants: copyMap(consonants),
+		compounds:  copyMap(compounds),
+		modifiers:  copyMap(modifiers),
+		maxMods:    defaultMaxMods,
+	}
+	tl.apply(opts)
+	return tl
+}
+
+// apply applies opts to k and updates the state derived from the maps.
+func (k *TLPhone) apply(opts []Option) {
+	for _, o := range opts {
+		o(k)
+	}
+
+	k.maxRunes = 0
+	for _, m := range []map[string]string{k.vowels, k.consonants, k.compounds, k.modifiers} {
+		for g := range m {
+			if n := utf8.RuneCountInString(g); n > k.maxRunes {
+				k.maxRunes = n
+			}
+		}
+	}
+	if k.maxRunes > maxGlyphRunes {
+		k.maxRunes = maxGlyphRunes
+	}
+}
+
+// clone returns a copy of k that options can be applied to without
+// affecting k.
+func (k *TLPhone) clone() *TLPhone {
+	c := *k
+	c.vowels = copyMap(k.vowels)
+	c.consonants = copyMap(k.consonants)
+	c.compounds = copyMap(k.compounds)
+	c.modifiers = copyMap(k.modifiers)
+	c.stripPrefixes = append([]string(nil), k.stripPrefixes...)
+	if k.suffixes != nil {
+		c.suffixes = append([]string{}, k.suffixes...)
+	}
+	return &c
+}
+
+// WouldChange reports whether any key of input would differ if opts were
+// applied on top of the current configuration of k, which is left as is.
+func (k *TLPhone) WouldChange(input string, opts ...Option) bool {
+	c := k.clone()
+	c.apply(opts)
+	return c.EncodeResult(input) != k.EncodeResult(input)
+}
+
+func copyMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
+
+func (k *TLPhone) Encode(input string) (string, string, string) {
+	r := k.EncodeResult(input)
+	return r.Key0, r.Key1, r.Key2
+}
+
+// EncodeResult is like Encode but returns the keys as a Result.
+func (k *TLPhone) EncodeResult(input string) Result {
+	return k.encode(k.clean(input), &EncodeBuffer{})
+}
+
+// EncodeWords splits input on whitespace and encodes each word. Words that
+// produce no codes at all are skipped.
+func (k *TLPhone) EncodeWords(input string) []Result {
+	var out []Result
+	for _, w := range strings.Fields(separateSymbols(k, text{s: input}).s) {
+		if r := k.EncodeResult(w); r.Key2 != "" {
+			out = append(out, r)
+		}
+	}
+	return out
 }
 
-func New() *TLPhone {
+// EncodeBuffer is scratch space for EncodeInto. The zero value is ready to
+// use. Reusing one buffer across calls, for example through a sync.Pool,
+// avoids all allocations but the one holding the returned keys. A buffer
+// must not be used by more than one goroutine at a time; the returned
+// Result does not refer to it and stays valid after the buffer is reused.
+type EncodeBuffer struct {
+	keys []byte
+
+	// ends holds the end offset of each code token in keys; it is only
+	// filled in when tokenize is set.
+	ends     []int
+	tokenize bool
+
+	last string // the last code appended to keys
+}
+
+// EncodeInto is like EncodeResult but works in the scratch space of buf.
+func (k *TLPhone) EncodeInto(input string, buf *EncodeBuffer) Result {
+	return k.encode(k.clean(input), buf)
+}
+
+// EncodeDetectTruncation encodes input and additionally reports whether it
+// ends in a virama, i.e. a dangling half-consonant or incomplete conjunct,
+// which usually means the word was cut short.
+func (k *TLPhone) EncodeDetectTruncation(input string) (Result, bool) {
+	input = k.clean(input)
+	return k.encode(input, &EncodeBuffer{}), strings.HasSuffix(input, virama)
+}
+
+// EncodeWithConfidence encodes input and also returns the fraction of its
+// runes, whitespace aside, that were mapped to a glyph. Input that is mostly
+// in another script, or garbled, scores low.
+func (k *TLPhone) EncodeWithConfidence(input string) (Result, float64) {
 	var (
-		glyphs []string
-		mods   []string
-		tl     = &TLPhone{}
+		prepared = k.prepare(input)
+		total    int
 	)
+	for _, r := range prepared {
+		if !unicode.IsSpace(r) {
+			total++
+		}
+	}
+	if total == 0 {
+		return Result{}, 0
+	}
 
-	for k := range modifiers {
-		mods = append(mods, k)
+	input, mapped := k.mappedRunes(prepared, scriptStages)
+	return k.encode(input, &EncodeBuffer{}), float64(mapped) / float64(total)
+}
+
+// mappedRunes runs input through stages, as track does, and also returns
+// how many of its runes, whitespace aside, reached a glyph the encoder
+// matched.
+func (k *TLPhone) mappedRunes(input string, stages []stage) (string, int) {
+	var (
+		clean, kept = k.track(input, stages)
+		mapped, i   int
+	)
+	for _, r := range input {
+		if kept[i] && !unicode.IsSpace(r) {
+			mapped++
+		}
+		i++
 	}
+	return clean, mapped
+}
+
+// encode builds all three keys of the cleaned input in buf and returns
+// them as slices of a single string.
+func (k *TLPhone) encode(input string, buf *EncodeBuffer) Result {
+	buf.keys, buf.ends, buf.last = buf.keys[:0], buf.ends[:0], ""
+	k.appendCodes(input, buf)
+	return k.finishKeys(buf, strings.HasSuffix(input, virama))
+}
+
+// appendCodes appends the key2 codes of the cleaned input to the key2
+// held in buf.
+func (k *TLPhone) appendCodes(input string, buf *EncodeBuffer) {
+	k.scan(input, func(_, code string) {
+		k.appendCode(buf, code)
+	})
+}
 
-	for k := range compounds {
-		glyphs = append(glyphs, k)
+// appendCode appends one code to the key2 held in buf.
+func (k *TLPhone) appendCode(buf *EncodeBuffer, code string) {
+	if code == "" {
+		return
 	}
-	tl.modCompounds = regexp.MustCompile(`((` + strings.Join(glyphs, "|") + `)(` + strings.Join(mods, "|") + `))`)
+	if k.delimit && len(buf.keys) > 0 {
+		buf.keys = append(buf.keys, CodeDelimiter)
+	}
+	buf.keys = append(buf.keys, code...)
+	if buf.tokenize {
+		buf.ends = append(buf.ends, len(buf.keys))
+	}
+	buf.last = code
+}
+
+// keysOf returns the keys of a word spelled by steps, as encode builds
+// them from the codes of its glyphs, for words respelled step by step.
+func (k *TLPhone) keysOf(steps []Step) Result {
+	var buf EncodeBuffer
+	return k.finishKeys(k.stepBuffer(&buf, steps))
+}
 
-	glyphs = []string{}
-	for k := range consonants {
-		glyphs = append(glyphs, k)
+// key0Of is keysOf for part of a word, such as a syllable, and returns
+// only key0. WithKeyTransform is not applied, as it is meant for the keys
+// of whole words.
+func (k *TLPhone) key0Of(steps []Step) string {
+	var buf EncodeBuffer
+	return k.deriveKeys(k.stepBuffer(&buf, steps)).Key0
+}
+
+// stepBuffer appends the codes of steps to buf and returns it, with
+// whether steps end in a virama.
+func (k *TLPhone) stepBuffer(buf *EncodeBuffer, steps []Step) (*EncodeBuffer, bool) {
+	for _, s := range steps {
+		k.appendCode(buf, s.Code)
 	}
-	tl.modConsonants = regexp.MustCompile(`((` + strings.Join(glyphs, "|") + `)(` + strings.Join(mods, "|") + `))`)
+	return buf, len(steps) > 0 && steps[len(steps)-1].Glyph == virama
+}
 
-	glyphs = []string{}
-	for k := range vowels {
-		glyphs = append(glyphs, k)
+// finishKeys derives key1 and key0 from the key2 in buf and returns all
+// three, passed through WithKeyTransform. dangling reports whether the
+// input ended in a virama.
+func (k *TLPhone) finishKeys(buf *EncodeBuffer, dangling bool) Result {
+	r := k.deriveKeys(buf, dangling)
+	if k.keyTransform != nil {
+		r.Key0 = k.keyTransform(0, r.Key0)
+		r.Key1 = k.keyTransform(1, r.Key1)
+		r.Key2 = k.keyTransform(2, r.Key2)
 	}
-	tl.modVowels = regexp.MustCompile(`((` + strings.Join(glyphs, "|") + `)(` + strings.Join(mods, "|") + `))`)
+	return r
+}
 
-	return tl
+// deriveKeys is finishKeys without WithKeyTransform.
+func (k *TLPhone) deriveKeys(buf *EncodeBuffer, dangling bool) Result {
+	drop1, drop0 := key1Drop, key0Drop
+	if k.delimit {
+		drop1, drop0 = key1Drop+string(CodeDelimiter), key0Drop+string(CodeDelimiter)
+	}
+	b := buf.keys
+	n2 := len(b)
+	b = appendReduced(b, b[:n2], drop1)
+	n1 := len(b)
+	b = appendReduced(b, b[:n2], drop0)
+	if k.finalDevoicing && dangling {
+		if c, ok := devoiced[buf.last]; ok {
+			b[len(b)-1] = c
+		}
+	}
+	if k.foldT {
+		for i := n1; i < len(b); i++ {
+			if b[i] == dentalT {
+				b[i] = 'T'
+			}
+		}
+	}
+	buf.keys = b
+
+	keys := string(b)
+	return Result{Key0: keys[n1:], Key1: keys[n2:n1], Key2: keys[:n2]}
 }
 
-func (k *TLPhone) Encode(input string) (string, string, string) {
-	key2 := k.process(input)
-	key1 := regexKey1.ReplaceAllString(key2, "")
-	key0 := regexKey0.ReplaceAllString(key2, "")
-	return key0, key1, key2
+func appendReduced(dst, key2 []byte, drop string) []byte {
+	for _, c := range key2 {
+		if strings.IndexByte(drop, c) < 0 {
+			dst = append(dst, c)
+		}
+	}
+	return dst
 }
 
-func (k *TLPhone) process(input string) string {
-	input = regexNonTulu.ReplaceAllString(strings.TrimSpace(input), "")
+// legacyOrthography maps letters dropped from modern Kannada and Tulu
+// spelling to the ones that replaced them, for WithLegacyOrthography.
+var legacyOrthography = newReplacer(
+	"ಱ", "ರ", // RRA
+	"ೞ", "ಳ", // LLLA
+	"ೝ", "ನ್", // NAKAARA POLLU
+	"ೱ", "ಃ", // JIHVAMULIYA
+	"ೲ", "ಃ", // UPADHMANIYA
+)
 
-	input = k.replaceModifiedGlyphs(input, compounds, k.modCompounds)
-	for ck, cv := range compounds {
-		input = strings.ReplaceAll(input, ck, `{`+cv+`}`)
+// homoglyphs maps spellings that render like another glyph to that glyph,
+// for WithHomoglyphFolding. The vowel sequences are those the Unicode
+// standard tells not to use for the vowel letters they look like.
+var homoglyphs = newReplacer(
+	"\u0c85\u0cbe", "\u0c86", // ಅ ಾ -> ಆ
+	"\u0c87\u0cd5", "\u0c88", // ಇ ೕ -> ಈ
+	"\u0c89\u0cbe", "\u0c8a", // ಉ ಾ -> ಊ
+	"\u0c92\u0ccc", "\u0c94", // ಒ ೌ -> ಔ
+)
+
+// isConsonant reports whether r is a Kannada consonant letter.
+func isConsonant(r rune) bool {
+	return r >= '\u0c95' && r <= '\u0cb9' || r == '\u0cde'
+}
+
+// isVowelSign reports whether r is a Kannada dependent vowel sign.
+func isVowelSign(r rune) bool {
+	return r >= '\u0cbe' && r <= '\u0ccc' || r == '\u0ce2' || r == '\u0ce3'
+}
+
+// dropUnkept is the strings.Map function of filterScript.
+func (k *TLPhone) dropUnkept(r rune) rune {
+	if !k.keeps(r) {
+		return -1
 	}
-	input = k.replaceModifiedGlyphs(input, consonants, k.modConsonants)
-	input = k.replaceModifiedGlyphs(input, vowels, k.modVowels)
-	for ck, cv := range consonants {
-		input = strings.ReplaceAll(input, ck, `{`+cv+`}`)
+	return r
+}
+
+// keeps reports whether filterScript keeps r.
+func (k *TLPhone) keeps(r rune) bool {
+	return unicode.Is(unicode.Kannada, r) || k.latin && isLatin(r) || k.spaceCode != "" && unicode.IsSpace(r)
+}
+
+// vowelSignComposer maps the canonical decompositions of the two-part vowel
+// signs back to their composed forms, which are what the modifiers map
+// holds. Longer sequences come first so ೆ+ೂ+ೕ composes to ೋ, not ೊ+ೕ.
+var vowelSignComposer = newReplacer(
+	"\u0cc6\u0cc2\u0cd5", "\u0ccb", // ೆ ೂ ೕ -> ೋ
+	"\u0cca\u0cd5", "\u0ccb", // ೊ ೕ -> ೋ
+	"\u0cc6\u0cc2", "\u0cca", // ೆ ೂ -> ೊ
+	"\u0cc6\u0cd5", "\u0cc7", // ೆ ೕ -> ೇ
+	"\u0cc6\u0cd6", "\u0cc8", // ೆ ೖ -> ೈ
+	"\u0cbf\u0cd5", "\u0cc0", // ಿ ೕ -> ೀ
+)
+
+// markRank returns the position the maps expect the combining mark r in
+// among the marks on one base, or -1 if r is not a mark: the nukta first,
+// then a vowel sign or virama, the length marks and last the nasal signs
+// and visarga.
+func markRank(r rune) int {
+	switch {
+	case r == '\u0cbc':
+		return 0
+	case r >= '\u0cbe' && r <= '\u0ccd', r == '\u0ce2', r == '\u0ce3':
+		return 1
+	case r == '\u0cd5', r == '\u0cd6':
+		return 2
+	case r >= '\u0c81' && r <= '\u0c83', r == '\u0cf3':
+		return 3
+	}
+	return -1
+}
+
+// isLatin reports whether r is an ASCII letter.
+func isLatin(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// dropFormatting removes, as a strings.Map function, the directional marks
+// from RTL documents and the joiners that select half-forms. They are
+// removed explicitly rather than left to the script filter, so an explicit
+// half-form spells the same as its conjunct.
+func dropFormatting(r rune) rune {
+	if isBidi(r) || isJoiner(r) {
+		return -1
 	}
-	for vk, vv := range vowels {
-		input = strings.ReplaceAll(input, vk, `{`+vv+`}`)
+	return r
+}
+
+// symbolToSpace is the strings.Map function of separateSymbols.
+func symbolToSpace(r rune) rune {
+	if unicode.IsSymbol(r) {
+		return ' '
 	}
-	for mk, mv := range modifiers {
-		input = strings.ReplaceAll(input, mk, mv)
+	return r
+}
+
+// isJoiner reports whether r is a zero width joiner or non-joiner.
+func isJoiner(r rune) bool {
+	return r == '\u200c' || r == '\u200d'
+}
+
+// isBidi reports whether r is a bidirectional formatting character.
+func isBidi(r rune) bool {
+	switch {
+	case r == '\u061c', r == '\u200e', r == '\u200f':
+		return true
+	case r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069':
+		return true
 	}
+	return false
+}
 
-	return regexAlphaNum.ReplaceAllString(input, "")
+// tokens returns the non-empty codes of input in order. Joined, they form
+// its key2.
+func (k *TLPhone) tokens(input string) []string {
+	var out []string
+	k.scan(input, func(_, code string) {
+		if code != "" {
+			out = append(out, code)
+		}
+	})
+	return out
 }
 
-func (k *TLPhone) replaceModifiedGlyphs(input string, glyphs map[string]string, r *regexp.Regexp) string {
-	for _, matches := range r.FindAllStringSubmatch(input, -1) {
-		for _, m := range matches {
-			if rep, ok := glyphs[m]; ok {
-				input = strings.ReplaceAll(input, m, rep)
+// scan walks input left to right, matching the longest known glyph at each
+// position, and calls fn with every glyph and its code. Runes that match
+// no glyph, and modifiers beyond the WithMaxModifiersPerBase limit, are
+// skipped.
+func (k *TLPhone) scan(input string, fn func(glyph, code string)) {
+	k.walk(input, fn, nil)
+}
+
+// walk is scan that also calls skip, unless it is nil, with each rune or
+// modifier that it skips.
+func (k *TLPhone) walk(input string, fn func(glyph, code string), skip func(s string)) {
+	var (
+		// lengthens is whether an avagraha at this point follows a vowel
+		// it lengthens, when WithAvagrahaAsLength is on.
+		lengthens bool
+		// mods counts the modifiers since the last other glyph.
+		mods int
+	)
+	for i := 0; i < len(input); {
+		glyph, code := k.match(input[i:])
+		if glyph == "" && k.avagrahaLength && lengthens && strings.HasPrefix(input[i:], avagraha) {
+			glyph, code = avagraha, string(LengthCode)
+		}
+		if glyph == "" {
+			_, size := utf8.DecodeRuneInString(input[i:])
+			if skip != nil {
+				skip(input[i : i+size])
+			}
+			i += size
+			continue
+		}
+		if _, ok := k.modifiers[glyph]; !ok {
+			mods = 0
+		} else if mods++; k.maxMods > 0 && mods > k.maxMods {
+			if skip != nil {
+				skip(glyph)
+			}
+			i += len(glyph)
+			continue
+		}
+		fn(glyph, code)
+		i += len(glyph)
+		if k.avagrahaLength {
+			lengthens = k.endsInVowel(glyph, lengthens)
+		}
+	}
+}
+
+// endsInVowel reports whether glyph ends in a vowel an avagraha after it
+// can lengthen: a vowel, a vowel sign, or a consonant with its inherent
+// vowel. The anusvara and visarga, and the avagraha itself, keep what the
+// glyph before them ended in, given as prev.
+func (k *TLPhone) endsInVowel(glyph string, prev bool) bool {
+	switch {
+	case glyph == anusvara, glyph == visarga, glyph == avagraha:
+		return prev
+	case glyph == virama, glyph == repha:
+		return false
+	}
+	switch k.classOf(glyph) {
+	case ClassVowel, ClassConsonant, ClassCompound, ClassModifier:
+		return true
+	}
+	return false
+}
+
+// match returns the longest glyph at the start of s and its code, or an
+// empty glyph if none is known.
+func (k *TLPhone) match(s string) (string, string) {
+	if k.spaceCode != "" {
+		n := 0
+		for i, r := range s {
+			if !unicode.IsSpace(r) {
+				break
+			}
+			n = i + utf8.RuneLen(r)
+		}
+		if n > 0 {
+			return s[:n], k.spaceCode
+		}
+	}
+	if k.latin && isLatin(rune(s[0])) {
+		n := 1
+		for n < len(s) && isLatin(rune(s[n])) {
+			n++
+		}
+		return s[:n], strings.ToUpper(s[:n])
+	}
+
+	if k.rephaCode != "" && strings.HasPrefix(s, repha) {
+		next, _ := utf8.DecodeRuneInString(s[len(repha):])
+		if _, ok := k.consonants[string(next)]; ok {
+			return repha, k.rephaCode
+		}
+	}
+
+	// Try the longest candidate first. At each length compounds take
+	// precedence, then consonants, vowels and modifiers.
+	var (
+		ends [maxGlyphRunes]int
+		n    int
+	)
+	for i := range s {
+		if i > 0 {
+			ends[n] = i
+			n++
+			if n >= k.maxRunes {
+				break
 			}
 		}
 	}
-	return input
+	if n < k.maxRunes {
+		ends[n] = len(s)
+		n++
+	}
+
+	for j := n - 1; j >= 0; j-- {
+		g := s[:ends[j]]
+		if code, ok := k.compounds[g]; ok {
+			return g, code
+		}
+		if code, ok := k.consonants[g]; ok {
+			return g, code
+		}
+		if code, ok := k.vowels[g]; ok {
+			return g, code
+		}
+		if code, ok := k.modifiers[g]; ok {
+			return g, code
+		}
+	}
+	return "", ""
 }
@@ -1,11 +1,28 @@
 package tlphone_test
 
 import (
+	"reflect"
 	"testing"
 
 	tlphone "github.com/deepakpadukone20/tlphone"
 )
 
+// encodeTests are words with their keys under New, shared by the tests of
+// the other entry points that must agree with Encode.
+var encodeTests = []struct {
+	input      string
+	expectKey0 string
+	expectKey1 string
+	expectKey2 string
+}{
+	{"ತುಂಬಾ", "03B", "03B", "053B"},
+	{"ಮಕ್ಕಳು", "MKL", "MKL1", "MK2L15"},
+	{"ಬಂಗಾರಾ", "B3KR", "B3KR", "B3KR"},
+	{"ಅನುಗ್ರಹ", "ANKRH", "ANKRH", "AN5KRH"},
+	{"ವೃತ್ತಿ", "VR0", "VR0", "VR04"},
+	{"ಅಧ್ಯಕ್ಷ", "A0YKS", "A0YKS1", "A0YKS1"},
+}
+
 func TestEncode(t *testing.T) {
 	tests := []struct {
 		input      string
@@ -35,3 +52,229 @@ func TestEncode(t *testing.T) {
 		}
 	}
 }
+
+func TestEncodeGeminateNasals(t *testing.T) {
+	tests := []struct {
+		input      string
+		expectKey0 string
+		expectKey1 string
+		expectKey2 string
+	}{
+		{"ಅನ್ನ", "AN", "AN", "AN2"},
+		{"ಕಣ್ಣ್", "KN", "KN1", "KN12"},
+		{"ಅನ", "AN", "AN", "AN"},
+		{"ಕಣ", "KN", "KN1", "KN1"},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		k0, k1, k2 := p.Encode(test.input)
+		if k0 != test.expectKey0 {
+			t.Errorf("Key0 mismatch for input '%s': got=%s want=%s", test.input, k0, test.expectKey0)
+		}
+		if k1 != test.expectKey1 {
+			t.Errorf("Key1 mismatch for input '%s': got=%s want=%s", test.input, k1, test.expectKey1)
+		}
+		if k2 != test.expectKey2 {
+			t.Errorf("Key2 mismatch for input '%s': got=%s want=%s", test.input, k2, test.expectKey2)
+		}
+	}
+}
+
+func TestEncodeBidi(t *testing.T) {
+	tests := []struct {
+		input string
+		clean string
+	}{
+		{"\u200fಮಕ್ಕಳು\u200e", "ಮಕ್ಕಳು"},
+		{"ತುಂ\u200eಬಾ", "ತುಂಬಾ"},
+		{"\u202bಅನುಗ್ರಹ\u202c", "ಅನುಗ್ರಹ"},
+		{"\u2067ವೃತ್ತಿ\u2069", "ವೃತ್ತಿ"},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		k0, k1, k2 := p.Encode(test.input)
+		e0, e1, e2 := p.Encode(test.clean)
+		if k0 != e0 || k1 != e1 || k2 != e2 {
+			t.Errorf("Bidi mismatch for input '%s': got=%s,%s,%s want=%s,%s,%s", test.input, k0, k1, k2, e0, e1, e2)
+		}
+	}
+}
+
+func TestEncodeDetectTruncation(t *testing.T) {
+	tests := []struct {
+		input     string
+		truncated bool
+	}{
+		{"ಮಕ್ಕಳು", false},
+		{"ಅನುಗ್ರಹ", false},
+		{"ಅನುಗ್", true},
+		{"ಮಕ್", true},
+		{"ಮಕ್\u200d", true},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		r, truncated := p.EncodeDetectTruncation(test.input)
+		if truncated != test.truncated {
+			t.Errorf("Truncation mismatch for input '%s': got=%v want=%v", test.input, truncated, test.truncated)
+		}
+		if want := p.EncodeResult(test.input); r != want {
+			t.Errorf("Result mismatch for input '%s': got=%v want=%v", test.input, r, want)
+		}
+	}
+}
+
+func TestEncodeInto(t *testing.T) {
+	var (
+		p   = tlphone.New()
+		buf tlphone.EncodeBuffer
+	)
+	for i := 0; i < 2; i++ {
+		var results []tlphone.Result
+		for _, test := range encodeTests {
+			results = append(results, p.EncodeInto(test.input, &buf))
+		}
+		// Results must stay intact after the buffer has been reused.
+		for j, test := range encodeTests {
+			want := tlphone.Result{Key0: test.expectKey0, Key1: test.expectKey1, Key2: test.expectKey2}
+			if results[j] != want {
+				t.Errorf("EncodeInto mismatch for input '%s': got=%v want=%v", test.input, results[j], want)
+			}
+		}
+	}
+}
+
+func BenchmarkEncodeResult(b *testing.B) {
+	p := tlphone.New()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.EncodeResult(encodeTests[i%len(encodeTests)].input)
+	}
+}
+
+func BenchmarkEncodeInto(b *testing.B) {
+	var (
+		p   = tlphone.New()
+		buf tlphone.EncodeBuffer
+	)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p.EncodeInto(encodeTests[i%len(encodeTests)].input, &buf)
+	}
+}
+
+func TestEncodeHalfForms(t *testing.T) {
+	tests := []struct {
+		halfForm string
+		conjunct string
+	}{
+		{"ಕ್\u200dಕ", "ಕ್ಕ"},
+		{"ಚ್\u200dಚ", "ಚ್ಚ"},
+		{"ಟ್\u200dಟ", "ಟ್ಟ"},
+		{"ತ್\u200dತ", "ತ್ತ"},
+		{"ಪ್\u200dಪ", "ಪ್ಪ"},
+		{"ಲ್\u200dಲ", "ಲ್ಲ"},
+		{"ಕ್\u200dಷ", "ಕ್ಷ"},
+		{"ಮಕ್\u200dಕಳು", "ಮಕ್ಕಳು"},
+		{"ಮಕ್\u200cಕಳು", "ಮಕ್ಕಳು"},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		got, want := p.EncodeResult(test.halfForm), p.EncodeResult(test.conjunct)
+		if got.Key2 != want.Key2 {
+			t.Errorf("Key2 mismatch for half-form '%s': got=%s want=%s", test.halfForm, got.Key2, want.Key2)
+		}
+	}
+}
+
+func TestEncodeWords(t *testing.T) {
+	p := tlphone.New()
+	got := p.EncodeWords(" ತುಂಬಾ  ಮಕ್ಕಳು, ?? ವೃತ್ತಿ ")
+	want := []tlphone.Result{p.EncodeResult("ತುಂಬಾ"), p.EncodeResult("ಮಕ್ಕಳು"), p.EncodeResult("ವೃತ್ತಿ")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EncodeWords mismatch: got=%v want=%v", got, want)
+	}
+}
+
+func TestEncodeDecomposedVowels(t *testing.T) {
+	tests := []struct {
+		nfc        string
+		nfd        string
+		expectKey2 string
+	}{
+		{"ಕೊಡು", "ಕ\u0cc6\u0cc2ಡು", "K8T5"},
+		{"ಕೋಟೆ", "ಕ\u0cc6\u0cc2\u0cd5ಟೆ", "K8T6"},
+		{"ಕೋಟೆ", "ಕೊ\u0cd5ಟೆ", "K8T6"},
+		{"ಕೀಲಿ", "ಕ\u0cbf\u0cd5ಲಿ", "K4L4"},
+		{"ಬೇಡ", "ಬ\u0cc6\u0cd5ಡ", "B6T"},
+		{"ಕೈ", "ಕ\u0cc6\u0cd6", "K7"},
+		{"ಮೌನ", "ಮೌನ", "M9N"},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		for _, input := range []string{test.nfc, test.nfd} {
+			if got := p.EncodeResult(input).Key2; got != test.expectKey2 {
+				t.Errorf("Key2 mismatch for input %+q: got=%s want=%s", input, got, test.expectKey2)
+			}
+		}
+	}
+}
+
+func TestEncodeMarkOrder(t *testing.T) {
+	tests := []struct {
+		canonical  string
+		reordered  string
+		expectKey2 string
+	}{
+		{"ಕಿಂ", "ಕ\u0c82\u0cbf", "K43"},
+		{"ಮುಃ", "ಮ\u0c83\u0cc1", "M5"},
+		{"ಕೇಂದ್ರ", "ಕ\u0c82\u0cc6\u0cd5ದ್ರ", "K630R"},
+		{"ಜ಼ಿ", "ಜ\u0cbf\u0cbc", "G4"},
+		{"ಕಿಂಕಿಂ", "ಕ\u0c82\u0cbfಕ\u0cbf\u0c82", "K43K43"},
+	}
+
+	p := tlphone.New(tlphone.WithLoanwords(true))
+	for _, test := range tests {
+		for _, input := range []string{test.canonical, test.reordered} {
+			if got := p.EncodeResult(input).Key2; got != test.expectKey2 {
+				t.Errorf("Key2 mismatch for input %+q: got=%s want=%s", input, got, test.expectKey2)
+			}
+		}
+	}
+}
+
+func TestEncodeWithConfidence(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect float64
+	}{
+		{"ಮಕ್ಕಳು", 1},
+		{" ಮಕ್ಕಳು ", 1},
+		{"ಕ\u0cc6\u0cc2ಡು", 1},
+		{"ಮಕ್ಕಳು abcdef", 0.5},
+		{"ಮಕ್ಕಳು೧೨", 0.75},
+		{"abc", 0},
+		{"", 0},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		r, c := p.EncodeWithConfidence(test.input)
+		if c != test.expect {
+			t.Errorf("Confidence mismatch for input '%s': got=%v want=%v", test.input, c, test.expect)
+		}
+		if want := p.EncodeResult(test.input); r != want {
+			t.Errorf("Result mismatch for input '%s': got=%v want=%v", test.input, r, want)
+		}
+	}
+
+	_, clean := p.EncodeWithConfidence("ಅನುಗ್ರಹ")
+	_, mixed := p.EncodeWithConfidence("ಅನುಗ್ರಹ anugraha")
+	if mixed >= clean {
+		t.Errorf("Confidence for half-Latin input not lower: got=%v, clean=%v", mixed, clean)
+	}
+}
@@ -0,0 +1,307 @@
+package tlphone
+
+import "strings"
+
+// Step is one glyph of an input as matched by the encoder and the code it
+// contributed to key2. Code is empty for glyphs that only shape their base,
+// such as the virama.
+type Step struct {
+	Glyph string
+	Code  string
+}
+
+// Explain returns the glyphs input was split into, in order, with the code
+// each one was mapped to. Runes the encoder drops do not appear.
+func (k *TLPhone) Explain(input string) []Step {
+	var steps []Step
+	k.scan(k.clean(input), func(glyph, code string) {
+		steps = append(steps, Step{Glyph: glyph, Code: code})
+	})
+	return steps
+}
+
+// EncodeTokenized encodes input and also returns the code tokens of its
+// key2, in order, from the same pass. The tokens are substrings of Key2 and
+// concatenate to it, or join to it on CodeDelimiter with WithCodeDelimiter;
+// with WithKeyTransform they are those of key2 before the transform.
+func (k *TLPhone) EncodeTokenized(input string) (Result, []string) {
+	buf := EncodeBuffer{tokenize: true}
+	r := k.encode(k.clean(input), &buf)
+	if len(buf.ends) == 0 {
+		return r, nil
+	}
+
+	key2 := r.Key2
+	if k.keyTransform != nil {
+		key2 = string(buf.keys[:buf.ends[len(buf.ends)-1]])
+	}
+	toks := make([]string, len(buf.ends))
+	start := 0
+	for i, end := range buf.ends {
+		toks[i] = key2[start:end]
+		start = end
+		if k.delimit {
+			start++
+		}
+	}
+	return r, toks
+}
+
+// geminationMark is the character codes of doubled consonants carry, as
+// in the K2 of ಕ್ಕ.
+const geminationMark = '2'
+
+// GeminationPositions returns the indices, among the code tokens of the
+// key2 of input, of the tokens that carry the gemination marker. Doubled
+// consonants whose code has no marker, such as ತ್ತ, are not reported.
+func (k *TLPhone) GeminationPositions(input string) []int {
+	var out []int
+	for i, t := range k.tokens(k.clean(input)) {
+		if strings.IndexByte(t, geminationMark) >= 0 {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// CodeNGrams returns every contiguous window of n code tokens in the key2
+// of input, each joined into a string. Indexing these allows matching
+// words that contain a phonetic substring. It returns nil if n is less
+// than 1 or larger than the number of tokens.
+func (k *TLPhone) CodeNGrams(input string, n int) []string {
+	toks := k.tokens(k.clean(input))
+	if n < 1 || n > len(toks) {
+		return nil
+	}
+
+	out := make([]string, 0, len(toks)-n+1)
+	for i := 0; i+n <= len(toks); i++ {
+		out = append(out, strings.Join(toks[i:i+n], ""))
+	}
+	return out
+}
+
+// EditKind is the kind of an EditOp.
+type EditKind int
+
+const (
+	EditInsert EditKind = iota
+	EditDelete
+	EditSubstitute
+)
+
+// EditOp is a single code token edit. Pos is the index in the tokens of
+// the source key that the edit applies to; an insertion goes before the
+// token at Pos. From is empty for insertions and To for deletions.
+type EditOp struct {
+	Kind EditKind
+	Pos  int
+	From string
+	To   string
+}
+
+// KeyEditScript returns a shortest sequence of code token edits that turns
+// the key2 of a into the key2 of b, in source order.
+func (k *TLPhone) KeyEditScript(a, b string) []EditOp {
+	return editScript(k.tokens(k.clean(a)), k.tokens(k.clean(b)))
+}
+
+// Similarity scores how alike a and b sound, from 1 for equal key2 down
+// to 0: one minus the code token edit distance between them, divided by
+// the length of the longer one. It returns 0 if either has no codes.
+func (k *TLPhone) Similarity(a, b string) float64 {
+	return similarity(k.tokens(k.clean(a)), k.tokens(k.clean(b)))
+}
+
+func similarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	return 1 - float64(len(editScript(a, b)))/float64(n)
+}
+
+// MatchWithTransposition reports whether a and b share a non-empty key1,
+// or would if two adjacent code tokens of one of them were swapped, as in
+// a typo that transposes neighbouring letters.
+func (k *TLPhone) MatchWithTransposition(a, b string) bool {
+	x, y := k.levelTokens(a, key1Drop), k.levelTokens(b, key1Drop)
+	if len(x) == 0 || len(y) == 0 {
+		return false
+	}
+	if strings.Join(x, "") == strings.Join(y, "") {
+		return true
+	}
+	if len(x) != len(y) {
+		return false
+	}
+	i := 0
+	for i < len(x) && x[i] == y[i] {
+		i++
+	}
+	if i+1 >= len(x) || x[i] != y[i+1] || x[i+1] != y[i] {
+		return false
+	}
+	for i += 2; i < len(x); i++ {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// levelTokens returns the code tokens of input with the characters in
+// drop removed, leaving out tokens that become empty. With key1Drop or
+// key0Drop they join to key1 or key0.
+func (k *TLPhone) levelTokens(input, drop string) []string {
+	var out []string
+	for _, t := range k.tokens(k.clean(input)) {
+		if t = string(appendReduced(nil, []byte(t), drop)); t != "" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// editScript computes the Levenshtein alignment of two token slices.
+func editScript(a, b []string) []EditOp {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+
+	ops := make([]EditOp, 0, d[len(a)][len(b)])
+	for i, j := len(a), len(b); i > 0 || j > 0; {
+		switch {
+		case i > 0 && j > 0 && a[i-1] == b[j-1] && d[i][j] == d[i-1][j-1]:
+			i, j = i-1, j-1
+		case i > 0 && j > 0 && d[i][j] == d[i-1][j-1]+1:
+			ops = append(ops, EditOp{Kind: EditSubstitute, Pos: i - 1, From: a[i-1], To: b[j-1]})
+			i, j = i-1, j-1
+		case i > 0 && d[i][j] == d[i-1][j]+1:
+			ops = append(ops, EditOp{Kind: EditDelete, Pos: i - 1, From: a[i-1]})
+			i--
+		default:
+			ops = append(ops, EditOp{Kind: EditInsert, Pos: i, To: b[j-1]})
+			j--
+		}
+	}
+	for l, r := 0, len(ops)-1; l < r; l, r = l+1, r-1 {
+		ops[l], ops[r] = ops[r], ops[l]
+	}
+	return ops
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// Class is the kind of glyph a code token was produced by.
+type Class string
+
+const (
+	ClassVowel     Class = "vowel"
+	ClassConsonant Class = "consonant"
+	ClassCompound  Class = "compound"
+	ClassModifier  Class = "modifier"
+	// ClassNasal is the anusvara, which is a modifier but stands for a
+	// nasal consonant.
+	ClassNasal Class = "nasal"
+	// ClassOther covers Latin fallback runes and space codes.
+	ClassOther Class = "other"
+)
+
+// EncodeClasses returns the class of every code token of the key2 of input,
+// in order.
+func (k *TLPhone) EncodeClasses(input string) []Class {
+	var out []Class
+	k.scan(k.clean(input), func(glyph, code string) {
+		if code != "" {
+			out = append(out, k.classOf(glyph))
+		}
+	})
+	return out
+}
+
+func (k *TLPhone) classOf(glyph string) Class {
+	if glyph == repha {
+		return ClassConsonant
+	}
+	if _, ok := k.compounds[glyph]; ok {
+		return ClassCompound
+	}
+	if _, ok := k.consonants[glyph]; ok {
+		return ClassConsonant
+	}
+	if _, ok := k.vowels[glyph]; ok {
+		return ClassVowel
+	}
+	if _, ok := k.modifiers[glyph]; ok {
+		if glyph == anusvara {
+			return ClassNasal
+		}
+		return ClassModifier
+	}
+	return ClassOther
+}
+
+// Signature returns the consonant skeleton of input, the loosest key the
+// encoder offers: the codes of its consonants and compounds with their
+// digits removed and doubled consonants collapsed. Unlike key0, which
+// keeps independent vowels and the anusvara (3) and writes unrelated
+// adjacent consonants as they are, it drops all vowels and nasal signs and
+// treats a doubled consonant, a compound like ಗ್ಗ or a consonant joined to
+// itself by a virama, as single. Any other repeat keeps both consonants,
+// so ಮಮ, ಮಾಮ and ಮಿಮ all give MM while ಮ್ಮ gives M.
+func (k *TLPhone) Signature(input string) string {
+	var (
+		b      []byte
+		prev   string
+		joined bool
+	)
+	k.scan(k.clean(input), func(glyph, code string) {
+		c := k.classOf(glyph)
+		if c != ClassConsonant && c != ClassCompound {
+			// Only the virama joins the consonants around it.
+			joined = glyph == virama && prev != ""
+			if !joined {
+				prev = ""
+			}
+			return
+		}
+		// 0 is the dental consonant, the other digits are markers.
+		code = strings.Map(func(r rune) rune {
+			if r >= '1' && r <= '9' {
+				return -1
+			}
+			return r
+		}, code)
+		if !joined || code != prev {
+			b = append(b, code...)
+		}
+		prev, joined = code, false
+	})
+	return string(b)
+}
@@ -0,0 +1,232 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestCodeNGrams(t *testing.T) {
+	tests := []struct {
+		input  string
+		n      int
+		expect []string
+	}{
+		{"ಮಕ್ಕಳು", 2, []string{"MK2", "K2L1", "L15"}},
+		{"ಮಕ್ಕಳು", 3, []string{"MK2L1", "K2L15"}},
+		{"ಮಕ್ಕಳು", 4, []string{"MK2L15"}},
+		{"ಮಕ್ಕಳು", 5, nil},
+		{"ಮಕ್ಕಳು", 0, nil},
+		{"ವೃತ್ತಿ", 1, []string{"V", "R", "0", "4"}},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		got := p.CodeNGrams(test.input, test.n)
+		if !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("NGram mismatch for input '%s' (n=%d): got=%v want=%v", test.input, test.n, got, test.expect)
+		}
+	}
+}
+
+func TestEncodeTokenized(t *testing.T) {
+	var (
+		p   = tlphone.New()
+		dot = tlphone.New(tlphone.WithCodeDelimiter(true))
+	)
+	for _, test := range encodeTests {
+		r, toks := p.EncodeTokenized(test.input)
+		if want := p.EncodeResult(test.input); r != want {
+			t.Errorf("Result mismatch for input '%s': got=%v want=%v", test.input, r, want)
+		}
+		if got := strings.Join(toks, ""); got != r.Key2 {
+			t.Errorf("Token join mismatch for input '%s': got=%s want=%s", test.input, got, r.Key2)
+		}
+
+		r, toks = dot.EncodeTokenized(test.input)
+		if got := strings.Join(toks, string(tlphone.CodeDelimiter)); got != r.Key2 {
+			t.Errorf("Delimited token join mismatch for input '%s': got=%s want=%s", test.input, got, r.Key2)
+		}
+	}
+
+	if _, toks := p.EncodeTokenized("ಮಕ್ಕಳು"); !reflect.DeepEqual(toks, []string{"M", "K2", "L1", "5"}) {
+		t.Errorf("Tokens mismatch for input 'ಮಕ್ಕಳು': got=%v", toks)
+	}
+}
+
+func TestGeminationPositions(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []int
+	}{
+		{"ಮಕ್ಕಳು", []int{1}},
+		{"ಅಕ್ಕಲ್ಲ", []int{1, 2}},
+		{"ಕಣ್ಣು", []int{1}},
+		{"ಹತ್ತು", nil},
+		{"ಮಗಳು", nil},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.GeminationPositions(test.input); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("GeminationPositions mismatch for input '%s': got=%v want=%v", test.input, got, test.expect)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	p := tlphone.New()
+	want := []tlphone.Step{{"ಮ", "M"}, {"ಕ್ಕ", "K2"}, {"ಳ", "L1"}, {"ು", "5"}}
+	if got := p.Explain("ಮಕ್ಕಳು"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Explain mismatch for input 'ಮಕ್ಕಳು': got=%v want=%v", got, want)
+	}
+}
+
+func TestExplainLatinFallback(t *testing.T) {
+	tests := []struct {
+		input     string
+		expectKey string
+		latin     []string
+	}{
+		{"ಮಕ್ಕಳು iPhone", "MK2L15IPHONE", []string{"iPhone"}},
+		{"McDonald ತುಂಬಾ", "MCDONALD053B", []string{"McDonald"}},
+		{"Tulu-Nadu", "TULUNADU", []string{"TuluNadu"}},
+	}
+
+	var (
+		p     = tlphone.New(tlphone.WithLatinFallback(true))
+		plain = tlphone.New()
+	)
+	for _, test := range tests {
+		if got := p.EncodeResult(test.input).Key2; got != test.expectKey {
+			t.Errorf("Key2 mismatch for input '%s': got=%s want=%s", test.input, got, test.expectKey)
+		}
+
+		var latin []string
+		for _, s := range p.Explain(test.input) {
+			if s.Code != "" && s.Glyph != s.Code && s.Code == strings.ToUpper(s.Glyph) {
+				latin = append(latin, s.Glyph)
+			}
+		}
+		if !reflect.DeepEqual(latin, test.latin) {
+			t.Errorf("Latin fragments mismatch for input '%s': got=%v want=%v", test.input, latin, test.latin)
+		}
+
+		for _, s := range plain.Explain(test.input) {
+			if strings.ContainsAny(s.Glyph, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+				t.Errorf("Latin kept without fallback for input '%s': got=%v", test.input, s)
+			}
+		}
+	}
+}
+
+func TestKeyEditScript(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect []tlphone.EditOp
+	}{
+		{"ಮಕಳು", "ಮಕ್ಕಳು", []tlphone.EditOp{{Kind: tlphone.EditSubstitute, Pos: 1, From: "K", To: "K2"}}},
+		{"ತುಂಬಾ", "ತಂಬಾ", []tlphone.EditOp{{Kind: tlphone.EditDelete, Pos: 1, From: "5"}}},
+		{"ತಂಬಾ", "ತುಂಬಾ", []tlphone.EditOp{{Kind: tlphone.EditInsert, Pos: 1, To: "5"}}},
+		{"ಮಕ್ಕಳು", "ಮಕ್ಕಳು", []tlphone.EditOp{}},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		got := p.KeyEditScript(test.a, test.b)
+		if !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("Edit script mismatch for '%s' -> '%s': got=%v want=%v", test.a, test.b, got, test.expect)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect float64
+	}{
+		{"ಮಕ್ಕಳು", "ಮಕ್ಕಳು", 1},
+		{"ಮಕ್ಕಳು", "ಮಕ್ಕಳ", 0.75},
+		{"ಮಕ್ಕಳು", "ಮಗಳು", 0.75},
+		{"ಮಕ್ಕಳು", "ನೀರು", 0.25},
+		{"ಮಕ್ಕಳು", "", 0},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.Similarity(test.a, test.b); got != test.expect {
+			t.Errorf("Similarity mismatch for '%s' and '%s': got=%v want=%v", test.a, test.b, got, test.expect)
+		}
+	}
+}
+
+func TestMatchWithTransposition(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		expect bool
+	}{
+		{"ಮಕ್ಕಳು", "ಮಕ್ಕಳ", true},
+		{"ಮಕ್ಕಳು", "ಮಳಕು", true},
+		{"ಮಕ್ಕಳು", "ಕಮ್ಮಳು", true},
+		{"ಕಮಲತ", "ಮಕತಲ", false},
+		{"ಮಕ್ಕಳು", "ನೀರು", false},
+		{"", "", false},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.MatchWithTransposition(test.a, test.b); got != test.expect {
+			t.Errorf("Transposition match mismatch for '%s' and '%s': got=%v want=%v", test.a, test.b, got, test.expect)
+		}
+		if got := p.MatchWithTransposition(test.b, test.a); got != test.expect {
+			t.Errorf("Transposition match mismatch for '%s' and '%s': got=%v want=%v", test.b, test.a, got, test.expect)
+		}
+	}
+}
+
+func TestEncodeClasses(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []tlphone.Class
+	}{
+		{"ಮಕ್ಕಳು", []tlphone.Class{tlphone.ClassConsonant, tlphone.ClassCompound, tlphone.ClassConsonant, tlphone.ClassModifier}},
+		{"ಅಂಕ", []tlphone.Class{tlphone.ClassVowel, tlphone.ClassNasal, tlphone.ClassConsonant}},
+		{"ಕಾ", []tlphone.Class{tlphone.ClassConsonant}},
+		{"", nil},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.EncodeClasses(test.input); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("EncodeClasses mismatch for input '%s': got=%v want=%v", test.input, got, test.expect)
+		}
+		if got, toks := len(p.EncodeClasses(test.input)), p.CodeNGrams(test.input, 1); got != len(toks) {
+			t.Errorf("EncodeClasses length mismatch for input '%s': got=%d want=%d", test.input, got, len(toks))
+		}
+	}
+}
+
+func TestSignature(t *testing.T) {
+	tests := []struct {
+		inputs []string
+		expect string
+	}{
+		{[]string{"ಮಕ್ಕಳು", "ಮಕಳು", "ಮಗ್ಗಳು", "ಮೊಕ್ಕಲ್", "ಮಂಕಳ"}, "MKL"},
+		{[]string{"ಅನುಗ್ರಹ", "ಅನುಗ್ರಹಾ", "ಅನ್ನುಗ್ರಹ", "ಆನುಗ್ರಾಹ"}, "NKRH"},
+		{[]string{"ವೃತ್ತಿ", "ವೃತಿ"}, "V0"},
+		{[]string{"ಮಮ", "ಮಾಮ", "ಮಿಮ", "ಮಾಮಾ", "ಮಮ್ಮ"}, "MM"},
+		{[]string{"ಮ್ಮ", "ಮ್ಮಾ", "ಮ್ಮ್"}, "M"},
+		{[]string{"ಅ", ""}, ""},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		for _, input := range test.inputs {
+			if got := p.Signature(input); got != test.expect {
+				t.Errorf("Signature mismatch for input '%s': got=%s want=%s", input, got, test.expect)
+			}
+		}
+	}
+}
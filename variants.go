@@ -0,0 +1,154 @@
+package tlphone
+
+import "strings"
+
+// fold rewrites the steps of a word into a looser spelling.
+type fold func(k *TLPhone, steps []Step) []Step
+
+// recallFolds are the folds AllVariantKeys applies, in order.
+var recallFolds = []fold{foldNasals, foldOrthography, foldRepeats}
+
+// nasalCodes are the codes of the nasal consonants.
+var nasalCodes = map[string]bool{"NG": true, "NJ": true, "N1": true, "N": true, "M": true}
+
+// foldNasals writes the anusvara, and a nasal consonant closed by a virama
+// before another consonant, as N. Both spell the nasal that matches the
+// consonant after it, so ತುಂಬಾ and ತುಮ್ಬಾ are the same word.
+func foldNasals(k *TLPhone, steps []Step) []Step {
+	out := append([]Step(nil), steps...)
+	for i, s := range out {
+		switch {
+		case s.Glyph == anusvara:
+			out[i].Code = "N"
+		case nasalCodes[s.Code] && i+2 < len(out) && out[i+1].Glyph == virama:
+			if c := k.classOf(out[i+2].Glyph); c == ClassConsonant || c == ClassCompound {
+				out[i].Code = "N"
+			}
+		}
+	}
+	return out
+}
+
+// foldOrthography drops the retroflex/sibilant marker 1 and maps the old
+// letters ೞ and ಱ to their modern L and R, merging spellings like ಳ/ಲ,
+// ಣ/ನ and ಶ/ಸ that writers often confuse.
+func foldOrthography(_ *TLPhone, steps []Step) []Step {
+	out := append([]Step(nil), steps...)
+	for i := range out {
+		switch out[i].Code {
+		case "Z":
+			out[i].Code = "L"
+		case "R1":
+			out[i].Code = "R"
+		default:
+			out[i].Code = strings.Replace(out[i].Code, "1", "", -1)
+		}
+	}
+	return out
+}
+
+// foldRepeats drops the gemination marker 2 and collapses a code repeated
+// right after itself or across a virama, so single and doubled consonants
+// match. Any other step in between, even one without a code such as ಾ,
+// keeps both.
+func foldRepeats(_ *TLPhone, steps []Step) []Step {
+	var (
+		out  []Step
+		prev string
+	)
+	for _, s := range steps {
+		if len(s.Code) > 1 {
+			s.Code = strings.TrimSuffix(s.Code, "2")
+		}
+		if s.Code != "" && s.Code == prev {
+			continue
+		}
+		out = append(out, s)
+		if s.Glyph != virama {
+			prev = s.Code
+		}
+	}
+	return out
+}
+
+// AllVariantKeys returns the distinct keys of input for an exhaustive
+// posting list: its key0, key1 and key2, followed by the three keys of its
+// spelling after each of these recall folds in turn:
+//
+//   - nasal: the anusvara, and a nasal closed by a virama before a
+//     consonant, become N
+//   - orthographic: the marker 1 is dropped and ೞ/ಱ become L/R
+//   - repeats: gemination markers are dropped and repeats collapsed
+func (k *TLPhone) AllVariantKeys(input string) []string {
+	var (
+		r    = k.EncodeResult(input)
+		out  []string
+		seen = make(map[string]bool)
+	)
+	add := func(keys ...string) {
+		for _, key := range keys {
+			if key != "" && !seen[key] {
+				seen[key] = true
+				out = append(out, key)
+			}
+		}
+	}
+	add(r.Key0, r.Key1, r.Key2)
+
+	steps := k.Explain(input)
+	for _, f := range recallFolds {
+		r := k.keysOf(f(k, steps))
+		add(r.Key0, r.Key1, r.Key2)
+	}
+	return out
+}
+
+// maxGeminationVariants caps the keys GeminationVariantKeys returns.
+const maxGeminationVariants = 32
+
+// GeminationVariantKeys returns the distinct key2s of input and of each
+// spelling of it with one consonant toggled between single and doubled,
+// as with ಕ and ಕ್ಕ, in the order of the consonants. Two spellings that
+// differ in the gemination of one or two consonants share a key. The keys
+// are built like Encode's, options included. At most 32 keys are returned,
+// the input's own first.
+func (k *TLPhone) GeminationVariantKeys(input string) []string {
+	var (
+		steps = k.Explain(input)
+		out   []string
+		seen  = make(map[string]bool)
+	)
+	add := func(key string) {
+		if key != "" && !seen[key] && len(out) < maxGeminationVariants {
+			seen[key] = true
+			out = append(out, key)
+		}
+	}
+	add(k.EncodeResult(input).Key2)
+
+	for i, s := range steps {
+		code, ok := k.toggleGemination(s.Glyph)
+		if !ok {
+			continue
+		}
+		variant := append([]Step(nil), steps...)
+		variant[i].Code = code
+		add(k.keysOf(variant).Key2)
+	}
+	return out
+}
+
+// toggleGemination returns the code of the doubled form of a single
+// consonant glyph, or of the single form of a doubled one, if the maps
+// have it.
+func (k *TLPhone) toggleGemination(glyph string) (string, bool) {
+	if _, ok := k.consonants[glyph]; ok {
+		code, ok := k.compounds[glyph+virama+glyph]
+		return code, ok
+	}
+	if single, ok := undoubled(glyph); ok {
+		code, ok := k.consonants[single]
+		return code, ok
+	}
+	return "", false
+}
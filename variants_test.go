@@ -0,0 +1,101 @@
+package tlphone_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tlphone "github.com/deepakpadukone20/tlphone"
+)
+
+func TestAllVariantKeys(t *testing.T) {
+	tests := []struct {
+		input  string
+		expect []string
+	}{
+		{"ಮಕ್ಕಳು", []string{"MKL", "MKL1", "MK2L15", "MK2L5", "MKL15"}},
+		{"ತುಂಬಾ", []string{"03B", "053B", "0NB", "05NB"}},
+		{"ತುಮ್ಬಾ", []string{"0MB", "05MB", "0NB", "05NB"}},
+		{"ಮನೆ", []string{"MN", "MN6"}},
+		{"ಕಾಕ", []string{"KK"}},
+		{"ಕಿಕ", []string{"KK", "K4K"}},
+		{"", nil},
+	}
+
+	p := tlphone.New()
+	for _, test := range tests {
+		if got := p.AllVariantKeys(test.input); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("AllVariantKeys mismatch for input '%s': got=%v want=%v", test.input, got, test.expect)
+		}
+	}
+
+	// The geminated and plain spellings meet in the folded keys.
+	var (
+		geminated = p.AllVariantKeys("ಮಕ್ಕಳು")
+		plain     = p.EncodeResult("ಮಕಳು").Key2
+	)
+	if !contains(geminated, "MK2L15") || !contains(geminated, plain) {
+		t.Errorf("AllVariantKeys %v missing unfolded MK2L15 or folded %s", geminated, plain)
+	}
+
+	// The folded spellings get their keys the way Encode builds them.
+	optTests := []struct {
+		input  string
+		opt    tlphone.Option
+		expect []string
+	}{
+		{"ಕತ್ತು", tlphone.WithFoldTSeries(true), []string{"KT", "K0", "K05"}},
+		{"ಕಬ್", tlphone.WithFinalDevoicing(true), []string{"KP", "KB"}},
+		{"ಮಕ್ಕಳು", tlphone.WithCodeDelimiter(true), []string{"MKL", "MKL1", "M.K2.L1.5", "M.K2.L.5", "M.K.L1.5"}},
+	}
+	for _, test := range optTests {
+		if got := tlphone.New(test.opt).AllVariantKeys(test.input); !reflect.DeepEqual(got, test.expect) {
+			t.Errorf("AllVariantKeys mismatch for input '%s' with option: got=%v want=%v", test.input, got, test.expect)
+		}
+	}
+}
+
+func TestGeminationVariantKeys(t *testing.T) {
+	p := tlphone.New()
+	if got, want := p.GeminationVariantKeys("ಮಕ್ಕಳು"), []string{"MK2L15", "M2K2L15", "MKL15", "MK2L125"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GeminationVariantKeys mismatch for input 'ಮಕ್ಕಳು': got=%v want=%v", got, want)
+	}
+
+	// Every variant key has the form of the input's own key2.
+	var (
+		delimited = tlphone.New(tlphone.WithCodeDelimiter(true))
+		prefixed  = tlphone.New(tlphone.WithKeyTransform(func(level int, key string) string {
+			return "tl:" + key
+		}))
+	)
+	if got, want := delimited.GeminationVariantKeys("ಮಕ್ಕಳು"), []string{"M.K2.L1.5", "M2.K2.L1.5", "M.K.L1.5", "M.K2.L12.5"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GeminationVariantKeys mismatch for input 'ಮಕ್ಕಳು' with delimiter: got=%v want=%v", got, want)
+	}
+	if got, want := prefixed.GeminationVariantKeys("ಮಕ್ಕಳು"), []string{"tl:MK2L15", "tl:M2K2L15", "tl:MKL15", "tl:MK2L125"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GeminationVariantKeys mismatch for input 'ಮಕ್ಕಳು' with key transform: got=%v want=%v", got, want)
+	}
+
+	tests := []struct {
+		a, b string
+	}{
+		{"ಮಕ್ಕಳು", "ಮಕಳು"},
+		{"ಅಕ್ಕ", "ಅಕ"},
+		{"ಕಣ್ಣು", "ಕಣು"},
+		{"ಮಕ್ಕಳ್ಳು", "ಮಕಳು"},
+	}
+	for _, test := range tests {
+		a, b := p.GeminationVariantKeys(test.a), p.GeminationVariantKeys(test.b)
+		shared := false
+		for _, key := range a {
+			shared = shared || contains(b, key)
+		}
+		if !shared {
+			t.Errorf("No shared gemination variant for '%s' and '%s': got=%v and %v", test.a, test.b, a, b)
+		}
+	}
+
+	long := strings.Repeat("ಮಕ", 40)
+	if got := len(p.GeminationVariantKeys(long)); got > 32 {
+		t.Errorf("GeminationVariantKeys not capped: got=%d keys", got)
+	}
+}